@@ -1,15 +1,16 @@
 package classdiagram
 
 import (
-	"errors"
 	"fmt"
-	"io"
 	"os"
-	"path/filepath"
 	"strings"
 
+	"github.com/keisuke-m123/godiagramgen/cmd/godiagramgen/output"
 	goplantuml "github.com/keisuke-m123/godiagramgen/diagram/class"
 	"github.com/keisuke-m123/godiagramgen/diagram/class/renderer"
+	"github.com/keisuke-m123/godiagramgen/diagram/directory"
+	"github.com/keisuke-m123/godiagramgen/diagram/filter"
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -22,21 +23,84 @@ const (
 	FlagTheme                  = "theme"
 	FlagRecursive              = "recursive"
 	FlagRenderExternalPackages = "render-external-packages"
+	FlagFormat                 = "format"
+	FlagShortPackageNames      = "short-package-names"
+	FlagRenderFunctions        = "render-functions"
+	FlagRenderConstants        = "render-constants"
+	FlagRenderVariables        = "render-variables"
+	FlagRenderMethodDeps       = "render-method-dependencies"
+	FlagFocus                  = "focus"
+	FlagDepth                  = "depth"
+	FlagFocusDirection         = "focus-direction"
+	FlagIncludePackages        = "include-packages"
+	FlagExcludePackages        = "exclude-packages"
+	FlagIncludeTypes           = "include-types"
+	FlagExcludeTypes           = "exclude-types"
+	FlagKinds                  = "kinds"
+	FlagExportedOnly           = "exported-only"
+	FlagAPISurface             = "api-surface"
+	FlagRenderTags             = "render-tags"
+	FlagTagKeys                = "tag-keys"
+	FlagRenderDocs             = "render-docs"
+	FlagCheck                  = "check"
+	FlagRender                 = "render"
+	FlagPlantUMLJar            = "plantuml-jar"
+)
+
+const (
+	FormatPlantUML = "plantuml"
+	FormatMermaid  = "mermaid"
+	FormatJSON     = "json"
+)
+
+const (
+	FocusDirectionBoth     = "both"
+	FocusDirectionOutgoing = "outgoing"
+	FocusDirectionIncoming = "incoming"
 )
 
 type FlagValues struct {
-	Ignore                 string
-	Title                  string
-	Notes                  string
+	Ignore string
+	Title  string
+	// Notes は図に追加する注釈を一行ずつ表す。 --notes フラグではカンマ区切りで指定する。
+	Notes                  []string
 	Output                 string
 	Theme                  string
 	Recursive              bool
 	RenderExternalPackages bool
+	Format                 string
+	ShortPackageNames      bool
+	RenderFunctions        bool
+	RenderConstants        bool
+	RenderVariables        bool
+	RenderMethodDeps       bool
+	Focus                  string
+	Depth                  int
+	FocusDirection         string
+	IncludePackages        string
+	ExcludePackages        string
+	IncludeTypes           string
+	ExcludeTypes           string
+	Kinds                  string
+	ExportedOnly           bool
+	APISurface             bool
+	RenderTags             bool
+	TagKeys                string
+	RenderDocs             bool
+	Check                  bool
+	Render                 string
+	PlantUMLJar            string
 }
 
 type FlagSet struct {
 	set    *pflag.FlagSet
 	values FlagValues
+	notes  string
+}
+
+// NewFlagSet は set にクラス図のフラグを定義するための FlagSet を生成する。
+func NewFlagSet(set *pflag.FlagSet) *FlagSet {
+	return &FlagSet{set: set}
 }
 
 func (fs *FlagSet) InitializeFlags() {
@@ -44,15 +108,41 @@ func (fs *FlagSet) InitializeFlags() {
 	vs := &fs.values
 	s.StringVar(&vs.Ignore, FlagIgnore, "", "Comma separated list of folders to ignore")
 	s.StringVar(&vs.Title, FlagTitle, "", "Title of the generated diagram")
-	s.StringVar(&vs.Notes, FlagNotes, "", "Comma separated list of notes to be added to the diagram")
+	s.StringVar(&fs.notes, FlagNotes, "", "Comma separated list of notes to be added to the diagram")
 	s.StringVar(&vs.Output, FlagOutput, "", "Output file path. If omitted, then this will default to standard output")
 	s.StringVar(&vs.Theme, FlagTheme, "", "Change theme")
 	s.BoolVar(&vs.Recursive, FlagRecursive, false, "Walk all directories recursively")
 	s.BoolVar(&vs.RenderExternalPackages, FlagRenderExternalPackages, false, "Render external packages")
+	s.StringVar(&vs.Format, FlagFormat, FormatPlantUML, "Output format (plantuml, mermaid, json)")
+	s.BoolVar(&vs.ShortPackageNames, FlagShortPackageNames, false, "Use package names instead of import paths for namespaces unless they are ambiguous")
+	s.BoolVar(&vs.RenderFunctions, FlagRenderFunctions, false, "Render package-level functions and relations from constructors to the types they create")
+	s.BoolVar(&vs.RenderConstants, FlagRenderConstants, false, "Render exported constants as values of their defined types")
+	s.BoolVar(&vs.RenderVariables, FlagRenderVariables, false, "Render package-level variables")
+	s.BoolVar(&vs.RenderMethodDeps, FlagRenderMethodDeps, false, "Render dependencies on types used in method parameters and results")
+	s.StringVar(&vs.Focus, FlagFocus, "", "Render only the specified type (pkg.TypeName) and the types around it")
+	s.IntVar(&vs.Depth, FlagDepth, 1, "Number of relation hops to follow from the focused type")
+	s.StringVar(&vs.FocusDirection, FlagFocusDirection, FocusDirectionBoth, "Direction of relations to follow from the focused type (both, outgoing, incoming)")
+	s.StringVar(&vs.IncludePackages, FlagIncludePackages, "", "Comma separated list of import path patterns of packages to render (glob, or regexp prefixed with re:)")
+	s.StringVar(&vs.ExcludePackages, FlagExcludePackages, "", "Comma separated list of import path patterns of packages not to render (glob, or regexp prefixed with re:)")
+	s.StringVar(&vs.IncludeTypes, FlagIncludeTypes, "", "Comma separated list of name patterns of types to render (glob, or regexp prefixed with re:)")
+	s.StringVar(&vs.ExcludeTypes, FlagExcludeTypes, "", "Comma separated list of name patterns of types not to render (glob, or regexp prefixed with re:)")
+	s.StringVar(&vs.Kinds, FlagKinds, "", "Comma separated list of kinds of types to render (struct, interface, constraint, defined, alias)")
+	s.BoolVar(&vs.ExportedOnly, FlagExportedOnly, false, "Render only exported types")
+	s.BoolVar(&vs.APISurface, FlagAPISurface, false, "Render only exported types and members, including exported members promoted from unexported embedded types")
+	s.BoolVar(&vs.RenderTags, FlagRenderTags, false, "Render struct tags next to fields")
+	s.StringVar(&vs.TagKeys, FlagTagKeys, "", "Comma separated list of struct tag keys to render (e.g. json,db). Implies --render-tags")
+	s.BoolVar(&vs.RenderDocs, FlagRenderDocs, false, "Render doc comments of types, fields and methods as notes attached to the types (JSON includes them as doc)")
+	s.BoolVar(&vs.Check, FlagCheck, false, "Compare the generated diagram with the output file instead of writing it, and fail with a unified diff if they differ")
+	s.StringVar(&vs.Render, FlagRender, "", "Render the diagram as an image (svg, png) with a local PlantUML jar file (or plantuml command) instead of writing the source")
+	s.StringVar(&vs.PlantUMLJar, FlagPlantUMLJar, "", "PlantUML jar file path used by --render. If omitted, then this will default to $PLANTUML_JAR, or plantuml command in PATH")
 }
 
 func (fs *FlagSet) Values() FlagValues {
-	return fs.values
+	values := fs.values
+	if fs.notes != "" {
+		values.Notes = strings.Split(fs.notes, ",")
+	}
+	return values
 }
 
 func NewClassDiagramGenCommand() *cobra.Command {
@@ -61,7 +151,7 @@ func NewClassDiagramGenCommand() *cobra.Command {
 		Short: "generate class diagram from specified packages",
 	}
 
-	fs := FlagSet{set: cmd.PersistentFlags()}
+	fs := NewFlagSet(cmd.PersistentFlags())
 	fs.InitializeFlags()
 
 	cmd.Run = func(cmd *cobra.Command, args []string) { run(fs.Values(), args) }
@@ -70,91 +160,151 @@ func NewClassDiagramGenCommand() *cobra.Command {
 }
 
 func run(flagValues FlagValues, args []string) {
-	var noteList []string
-	if flagValues.Notes != "" {
-		noteList = append(noteList, "<b><u>Notes</u></b>")
-	}
-	split := strings.Split(flagValues.Notes, ",")
-	for _, note := range split {
-		trimmed := strings.TrimSpace(note)
-		if trimmed != "" {
-			noteList = append(noteList, trimmed)
-		}
-	}
-	renderingOptions := &renderer.RenderingOptions{
-		Title:                  flagValues.Title,
-		Notes:                  strings.Join(noteList, "\n"),
-		Theme:                  flagValues.Theme,
-		RenderExternalPackages: flagValues.RenderExternalPackages,
-	}
-
-	dirs, err := getDirectories(args)
+	dirs, err := directory.FromArgs(args)
 	if err != nil {
 		fmt.Println("usage:\ngoplantuml <DIR>\nDIR Must be a valid directory")
 		_, _ = fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
-	ignoredDirectories, err := getIgnoredDirectories(flagValues.Ignore)
+	ignoredDirectories, err := directory.IgnoredFromList(flagValues.Ignore)
 	if err != nil {
 		fmt.Println("usage:\ngoplantuml [-ignore=<DIRLIST>]\nDIRLIST Must be a valid comma separated list of existing directories")
 		_, _ = fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
 
-	cd, err := goplantuml.NewDiagram(dirs, ignoredDirectories, flagValues.Recursive, renderingOptions)
-	if err != nil {
+	if err := Generate(flagValues, dirs, ignoredDirectories); err != nil {
 		_, _ = fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
+}
 
-	rendered := cd.Render()
-	var writer io.Writer
-	if flagValues.Output != "" {
-		writer, err = os.Create(flagValues.Output)
-		if err != nil {
-			_, _ = fmt.Fprintln(os.Stderr, err.Error())
-		}
-	} else {
-		writer = os.Stdout
+// Generate は dirs のクラス図を flagValues に従って生成し、 flagValues.Output (省略時は標準出力) に書き込む。
+// flagValues.Check の場合は書き込まずに flagValues.Output と比較し、異なる場合は output.StaleError を返す。
+// flagValues.Render を指定した場合は、 PlantUML で画像に変換して書き込む。
+//
+// flagValues.Ignore は参照せず、 ignoredDirectories を除外するディレクトリとする。
+func Generate(flagValues FlagValues, dirs []string, ignoredDirectories []string) error {
+	renderingOptions, err := newRenderingOptions(flagValues)
+	if err != nil {
+		return err
+	}
+	if err := ValidateRender(flagValues); err != nil {
+		return err
+	}
+
+	cd, err := goplantuml.NewDiagram(dirs, ignoredDirectories, flagValues.Recursive, renderingOptions)
+	if err != nil {
+		return err
+	}
+
+	var rendered string
+	switch flagValues.Format {
+	case FormatMermaid:
+		rendered = cd.RenderMermaid()
+	case FormatJSON:
+		rendered = cd.RenderJSON()
+	default:
+		rendered = cd.RenderPlantUML()
+	}
+	if flagValues.Render != "" {
+		return output.WriteImage(flagValues.Output, output.LanguagePlantUML, rendered, output.ImageOptions{
+			Format:      flagValues.Render,
+			PlantUMLJar: flagValues.PlantUMLJar,
+		})
 	}
-	_, _ = fmt.Fprint(writer, rendered)
+	if flagValues.Check {
+		return output.Check(flagValues.Output, rendered)
+	}
+	return output.Write(flagValues.Output, rendered)
 }
 
-func getDirectories(args []string) ([]string, error) {
-	if len(args) < 1 {
-		return nil, errors.New("DIR missing")
+// ValidateRender は flagValues.Render を指定した場合に、 PlantUML の図を画像に変換できるかを検証する。
+func ValidateRender(flagValues FlagValues) error {
+	if flagValues.Render == "" {
+		return nil
 	}
-	var dirs []string
-	for _, dir := range args {
-		fi, err := os.Stat(dir)
-		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("could not find directory %s", dir)
-		}
-		if !fi.Mode().IsDir() {
-			return nil, fmt.Errorf("%s is not a directory", dir)
-		}
-		dirAbs, err := filepath.Abs(dir)
-		if err != nil {
-			return nil, fmt.Errorf("could not find directory %s", dir)
-		}
-		dirs = append(dirs, dirAbs)
+	if flagValues.Format != FormatPlantUML {
+		return fmt.Errorf("--%s is not supported for %s format", FlagRender, flagValues.Format)
 	}
-	return dirs, nil
+	return output.ValidateImageFormat(flagValues.Render, flagValues.Check)
 }
 
-func getIgnoredDirectories(list string) ([]string, error) {
-	var result []string
-	list = strings.TrimSpace(list)
-	if list == "" {
-		return result, nil
-	}
-	split := strings.Split(list, ",")
-	for _, dir := range split {
-		dirAbs, err := filepath.Abs(strings.TrimSpace(dir))
-		if err != nil {
-			return nil, fmt.Errorf("could not find directory %s", dir)
+// Model は dirs のクラス図のモデルを flagValues に従って生成する。 flagValues の出力に関する値は参照しない。
+func Model(flagValues FlagValues, dirs []string, ignoredDirectories []string) (*model.Diagram, error) {
+	renderingOptions, err := newRenderingOptions(flagValues)
+	if err != nil {
+		return nil, err
+	}
+	cd, err := goplantuml.NewDiagram(dirs, ignoredDirectories, flagValues.Recursive, renderingOptions)
+	if err != nil {
+		return nil, err
+	}
+	return cd.Model(), nil
+}
+
+func newRenderingOptions(flagValues FlagValues) (*renderer.RenderingOptions, error) {
+	var noteList []string
+	for _, note := range flagValues.Notes {
+		trimmed := strings.TrimSpace(note)
+		if trimmed != "" {
+			noteList = append(noteList, trimmed)
+		}
+	}
+	if len(noteList) > 0 {
+		noteList = append([]string{"<b><u>Notes</u></b>"}, noteList...)
+	}
+	renderingOptions := &renderer.RenderingOptions{
+		Title:                    flagValues.Title,
+		Notes:                    strings.Join(noteList, "\n"),
+		Theme:                    flagValues.Theme,
+		RenderExternalPackages:   flagValues.RenderExternalPackages,
+		ShortPackageNames:        flagValues.ShortPackageNames,
+		RenderFunctions:          flagValues.RenderFunctions,
+		RenderConstants:          flagValues.RenderConstants,
+		RenderVariables:          flagValues.RenderVariables,
+		RenderMethodDependencies: flagValues.RenderMethodDeps,
+		Focus:                    flagValues.Focus,
+		FocusDepth:               flagValues.Depth,
+		APISurface:               flagValues.APISurface,
+		RenderTags:               flagValues.RenderTags,
+		RenderDocs:               flagValues.RenderDocs,
+	}
+	for _, key := range strings.Split(flagValues.TagKeys, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			renderingOptions.TagKeys = append(renderingOptions.TagKeys, key)
 		}
-		result = append(result, dirAbs)
 	}
-	return result, nil
+
+	switch flagValues.Format {
+	case FormatPlantUML, FormatMermaid, FormatJSON:
+	default:
+		return nil, fmt.Errorf("unsupported format %s", flagValues.Format)
+	}
+
+	f, err := filter.NewFilter(filter.Options{
+		IncludePackages: flagValues.IncludePackages,
+		ExcludePackages: flagValues.ExcludePackages,
+		IncludeTypes:    flagValues.IncludeTypes,
+		ExcludeTypes:    flagValues.ExcludeTypes,
+		Kinds:           flagValues.Kinds,
+		ExportedOnly:    flagValues.ExportedOnly,
+	})
+	if err != nil {
+		return nil, err
+	}
+	renderingOptions.Filter = f
+
+	switch flagValues.FocusDirection {
+	case FocusDirectionBoth:
+		renderingOptions.FocusDirection = model.FocusDirectionBoth
+	case FocusDirectionOutgoing:
+		renderingOptions.FocusDirection = model.FocusDirectionOutgoing
+	case FocusDirectionIncoming:
+		renderingOptions.FocusDirection = model.FocusDirectionIncoming
+	default:
+		return nil, fmt.Errorf("unsupported focus direction %s", flagValues.FocusDirection)
+	}
+
+	return renderingOptions, nil
 }
@@ -0,0 +1,92 @@
+package diffdiagram
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+type (
+	// baseTree は比較の基準とするツリーを表す。
+	baseTree struct {
+		// root は現在のディレクトリに対応する基準のツリーのディレクトリ。
+		root    string
+		cleanup func() error
+	}
+)
+
+// resolveBase は base をディレクトリ、またはディレクトリでない場合は git の ref として解決する。
+//
+// git の ref の場合は一時ディレクトリに git worktree として展開する。展開したツリーは cleanup で削除する。
+func resolveBase(base string, cwd string) (*baseTree, error) {
+	if fi, err := os.Stat(base); err == nil && fi.IsDir() {
+		root, err := filepath.Abs(base)
+		if err != nil {
+			return nil, err
+		}
+		return &baseTree{root: root, cleanup: func() error { return nil }}, nil
+	}
+
+	toplevel, err := git(cwd, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("%s is neither a directory nor a git ref: %w", base, err)
+	}
+	if _, err := git(cwd, "rev-parse", "--verify", "--quiet", base+"^{commit}"); err != nil {
+		return nil, fmt.Errorf("%s is neither a directory nor a git ref", base)
+	}
+	rel, err := filepath.Rel(toplevel, cwd)
+	if err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.MkdirTemp("", "godiagramgen-diff-")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := git(cwd, "worktree", "add", "--detach", tmp, base); err != nil {
+		_ = os.RemoveAll(tmp)
+		return nil, fmt.Errorf("failed to check out %s: %w", base, err)
+	}
+	return &baseTree{
+		root: filepath.Join(tmp, rel),
+		cleanup: func() error {
+			if _, err := git(cwd, "worktree", "remove", "--force", tmp); err != nil {
+				return err
+			}
+			return os.RemoveAll(tmp)
+		},
+	}, nil
+}
+
+// rebase は現在のディレクトリ cwd 配下の dirs を、基準のツリーの対応するディレクトリに変換する。
+// 基準のツリーに存在しないディレクトリは除外する。
+func (b *baseTree) rebase(cwd string, dirs []string) ([]string, error) {
+	var rebased []string
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(cwd, dir)
+		if err != nil {
+			return nil, err
+		}
+		if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("%s is outside of the current directory", dir)
+		}
+		baseDir := filepath.Join(b.root, rel)
+		if fi, err := os.Stat(baseDir); err != nil || !fi.IsDir() {
+			continue
+		}
+		rebased = append(rebased, baseDir)
+	}
+	return rebased, nil
+}
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,284 @@
+package diffdiagram
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/keisuke-m123/godiagramgen/cmd/godiagramgen/classdiagram"
+	"github.com/keisuke-m123/godiagramgen/cmd/godiagramgen/output"
+	"github.com/keisuke-m123/godiagramgen/cmd/godiagramgen/pkgdiagram"
+	"github.com/keisuke-m123/godiagramgen/diagram/diff"
+	"github.com/keisuke-m123/godiagramgen/diagram/directory"
+	"github.com/keisuke-m123/godiagramgen/diagram/emitter"
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+	"github.com/keisuke-m123/godiagramgen/diagram/pkg"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	FlagBase = "base"
+	FlagKind = "kind"
+)
+
+const (
+	KindClass   = "class"
+	KindPackage = "package"
+)
+
+var (
+	// classOnlyFlags は --kind=class の場合にのみ指定できるフラグを表す。
+	classOnlyFlags = []string{
+		classdiagram.FlagTitle,
+		classdiagram.FlagNotes,
+		classdiagram.FlagShortPackageNames,
+		classdiagram.FlagRenderFunctions,
+		classdiagram.FlagRenderConstants,
+		classdiagram.FlagRenderVariables,
+		classdiagram.FlagRenderMethodDeps,
+		classdiagram.FlagFocus,
+		classdiagram.FlagFocusDirection,
+		classdiagram.FlagIncludePackages,
+		classdiagram.FlagExcludePackages,
+		classdiagram.FlagIncludeTypes,
+		classdiagram.FlagExcludeTypes,
+		classdiagram.FlagKinds,
+		classdiagram.FlagExportedOnly,
+		classdiagram.FlagAPISurface,
+		classdiagram.FlagRenderTags,
+		classdiagram.FlagTagKeys,
+		classdiagram.FlagRenderDocs,
+	}
+	// packageOnlyFlags は --kind=package の場合にのみ指定できるフラグを表す。
+	packageOnlyFlags = []string{
+		pkgdiagram.FlagHideStdlib,
+		pkgdiagram.FlagHideThirdParty,
+		pkgdiagram.FlagCollapseThirdParty,
+	}
+)
+
+type FlagValues struct {
+	classdiagram.FlagValues
+	Base string
+	Kind string
+
+	// HideStdlib, HideThirdParty, CollapseThirdParty は --kind=package の場合に、 package コマンドの同名のフラグと同様に扱う。
+	HideStdlib         bool
+	HideThirdParty     bool
+	CollapseThirdParty bool
+}
+
+// FlagSet はクラス図のフラグに、基準と図の種類、パッケージ図のフラグを加えて定義する。
+type FlagSet struct {
+	set    *pflag.FlagSet
+	class  *classdiagram.FlagSet
+	values FlagValues
+}
+
+// NewFlagSet は set に diff コマンドのフラグを定義するための FlagSet を生成する。
+func NewFlagSet(set *pflag.FlagSet) *FlagSet {
+	return &FlagSet{set: set, class: classdiagram.NewFlagSet(set)}
+}
+
+func (fs *FlagSet) InitializeFlags() {
+	fs.class.InitializeFlags()
+	s := fs.set
+	vs := &fs.values
+	s.StringVar(&vs.Base, FlagBase, "", "Directory of another checkout, or git ref, to compare with (required)")
+	s.StringVar(&vs.Kind, FlagKind, KindClass, "Kind of diagram (class, package). With package, --recursive defaults to true and --depth collapses packages as in the package command")
+	s.BoolVar(&vs.HideStdlib, pkgdiagram.FlagHideStdlib, false, "Do not render standard library packages (package kind only)")
+	s.BoolVar(&vs.HideThirdParty, pkgdiagram.FlagHideThirdParty, false, "Do not render packages of other modules (package kind only)")
+	s.BoolVar(&vs.CollapseThirdParty, pkgdiagram.FlagCollapseThirdParty, false, "Collapse packages of other modules into their module root required in go.mod (package kind only)")
+}
+
+// Validate は --kind の図に適用できないフラグが指定された場合にエラーを返す。
+func (fs *FlagSet) Validate() error {
+	var unsupported []string
+	switch fs.values.Kind {
+	case KindClass:
+		unsupported = packageOnlyFlags
+	case KindPackage:
+		unsupported = classOnlyFlags
+	}
+	for _, name := range unsupported {
+		if fs.set.Changed(name) {
+			return fmt.Errorf("--%s is not supported for --%s=%s", name, FlagKind, fs.values.Kind)
+		}
+	}
+	return nil
+}
+
+// Values はフラグの値を返す。
+//
+// --kind=package の場合、省略された --recursive と --depth は package コマンドと同じ既定値(true と 0)とする。
+func (fs *FlagSet) Values() FlagValues {
+	values := fs.values
+	values.FlagValues = fs.class.Values()
+	if values.Kind == KindPackage {
+		if !fs.set.Changed(classdiagram.FlagRecursive) {
+			values.Recursive = true
+		}
+		if !fs.set.Changed(classdiagram.FlagDepth) {
+			values.Depth = 0
+		}
+	}
+	return values
+}
+
+func NewDiffCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff",
+		Short: "generate diagram highlighting added, removed and changed types, members and relations since base",
+	}
+
+	fs := NewFlagSet(cmd.PersistentFlags())
+	fs.InitializeFlags()
+
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		if err := fs.Validate(); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		run(fs.Values(), args)
+	}
+
+	return cmd
+}
+
+func run(flagValues FlagValues, args []string) {
+	dirs, err := directory.FromArgs(args)
+	if err != nil {
+		fmt.Println("usage:\ngodiagramgen diff --base=<DIR|REF> <DIR>\nDIR Must be a valid directory")
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	ignoredDirectories, err := directory.IgnoredFromList(flagValues.Ignore)
+	if err != nil {
+		fmt.Println("usage:\ngodiagramgen diff [-ignore=<DIRLIST>]\nDIRLIST Must be a valid comma separated list of existing directories")
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	if err := Generate(flagValues, dirs, ignoredDirectories); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+// Generate は flagValues.Base から dirs への変更を表す図を生成し、 flagValues.Output (省略時は標準出力) に書き込む。
+//
+// dirs と ignoredDirectories は現在のディレクトリ配下とし、基準のツリーの同じ相対パスのディレクトリと比較する。
+func Generate(flagValues FlagValues, dirs []string, ignoredDirectories []string) error {
+	if flagValues.Base == "" {
+		return fmt.Errorf("--%s is required", FlagBase)
+	}
+	if flagValues.Format != classdiagram.FormatPlantUML {
+		return fmt.Errorf("unsupported format %s", flagValues.Format)
+	}
+	if err := classdiagram.ValidateRender(flagValues.FlagValues); err != nil {
+		return err
+	}
+	var e emitter.Emitter
+	switch flagValues.Kind {
+	case KindClass:
+		e = emitter.NewPlantUMLClassEmitter()
+	case KindPackage:
+		e = emitter.NewPlantUMLPackageEmitter()
+	default:
+		return fmt.Errorf("unsupported kind %s", flagValues.Kind)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	// git の出力するパスと比較するため、シンボリックリンクを解決する。
+	if cwd, err = filepath.EvalSymlinks(cwd); err != nil {
+		return err
+	}
+	if dirs, err = evalSymlinks(dirs); err != nil {
+		return err
+	}
+	if ignoredDirectories, err = evalSymlinks(ignoredDirectories); err != nil {
+		return err
+	}
+
+	tree, err := resolveBase(flagValues.Base, cwd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tree.cleanup(); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		}
+	}()
+
+	baseDirs, err := tree.rebase(cwd, dirs)
+	if err != nil {
+		return err
+	}
+	baseIgnoredDirectories, err := tree.rebase(cwd, ignoredDirectories)
+	if err != nil {
+		return err
+	}
+
+	head, err := buildModel(flagValues, dirs, ignoredDirectories)
+	if err != nil {
+		return err
+	}
+	base := &model.Diagram{}
+	if len(baseDirs) > 0 {
+		if base, err = buildModel(flagValues, baseDirs, baseIgnoredDirectories); err != nil {
+			return fmt.Errorf("failed to load %s: %w", flagValues.Base, err)
+		}
+	}
+
+	rendered := e.Emit(diff.Compare(base, head))
+	if flagValues.Render != "" {
+		return output.WriteImage(flagValues.Output, output.LanguagePlantUML, rendered, output.ImageOptions{
+			Format:      flagValues.Render,
+			PlantUMLJar: flagValues.PlantUMLJar,
+		})
+	}
+	if flagValues.Check {
+		return output.Check(flagValues.Output, rendered)
+	}
+	return output.Write(flagValues.Output, rendered)
+}
+
+func buildModel(flagValues FlagValues, dirs []string, ignoredDirectories []string) (*model.Diagram, error) {
+	if flagValues.Kind == KindPackage {
+		pd, err := pkg.NewDiagramWithOption(dirs, ignoredDirectories, pkg.Options{
+			Theme:                  flagValues.Theme,
+			Recursive:              flagValues.Recursive,
+			Depth:                  flagValues.Depth,
+			RenderExternalPackages: flagValues.RenderExternalPackages,
+			HideStdlib:             flagValues.HideStdlib,
+			HideThirdParty:         flagValues.HideThirdParty,
+			CollapseThirdParty:     flagValues.CollapseThirdParty,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return pd.Model(), nil
+	}
+	return classdiagram.Model(flagValues.FlagValues, dirs, ignoredDirectories)
+}
+
+func evalSymlinks(dirs []string) ([]string, error) {
+	var result []string
+	for _, dir := range dirs {
+		evaluated, err := filepath.EvalSymlinks(dir)
+		if err != nil {
+			// 除外するディレクトリは存在しなくてもよいため、そのまま扱う。
+			if os.IsNotExist(err) {
+				result = append(result, dir)
+				continue
+			}
+			return nil, err
+		}
+		result = append(result, evaluated)
+	}
+	return result, nil
+}
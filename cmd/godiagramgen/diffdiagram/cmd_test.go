@@ -0,0 +1,53 @@
+package diffdiagram
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestFlagSet(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		wantErr       string
+		wantRecursive bool
+		wantDepth     int
+	}{
+		{name: "Class", args: []string{"--base=main"}, wantRecursive: false, wantDepth: 1},
+		{name: "Package", args: []string{"--base=main", "--kind=package"}, wantRecursive: true, wantDepth: 0},
+		{name: "PackageWithFlags", args: []string{"--base=main", "--kind=package", "--recursive=false", "--depth=2", "--hide-stdlib"}, wantRecursive: false, wantDepth: 2},
+		{name: "ClassOnlyFlag", args: []string{"--base=main", "--kind=package", "--focus=pkg.Type"}, wantErr: "--focus is not supported for --kind=package"},
+		{name: "PackageOnlyFlag", args: []string{"--base=main", "--collapse-third-party"}, wantErr: "--collapse-third-party is not supported for --kind=class"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			set := pflag.NewFlagSet("diff", pflag.ContinueOnError)
+			fs := NewFlagSet(set)
+			fs.InitializeFlags()
+			if err := set.Parse(test.args); err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			err := fs.Validate()
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Errorf("Validate() = %v, want %s", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			values := fs.Values()
+			if values.Recursive != test.wantRecursive {
+				t.Errorf("Recursive = %t, want %t", values.Recursive, test.wantRecursive)
+			}
+			if values.Depth != test.wantDepth {
+				t.Errorf("Depth = %d, want %d", values.Depth, test.wantDepth)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package erdiagram
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/keisuke-m123/godiagramgen/cmd/godiagramgen/output"
+	"github.com/keisuke-m123/godiagramgen/diagram/class"
+	"github.com/keisuke-m123/godiagramgen/diagram/class/renderer"
+	"github.com/keisuke-m123/godiagramgen/diagram/directory"
+	"github.com/keisuke-m123/godiagramgen/diagram/emitter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	FlagIgnore    = "ignore"
+	FlagTitle     = "title"
+	FlagOutput    = "output"
+	FlagRecursive = "recursive"
+	FlagFormat    = "format"
+	FlagCheck     = "check"
+
+	FlagRender      = "render"
+	FlagPlantUMLJar = "plantuml-jar"
+)
+
+const (
+	FormatPlantUML = "plantuml"
+	FormatMermaid  = "mermaid"
+)
+
+type FlagValues struct {
+	Ignore    string
+	Title     string
+	Output    string
+	Recursive bool
+	Format    string
+	Check     bool
+
+	Render      string
+	PlantUMLJar string
+}
+
+type FlagSet struct {
+	set    *pflag.FlagSet
+	values FlagValues
+}
+
+func (fs *FlagSet) InitializeFlags() {
+	s := fs.set
+	vs := &fs.values
+	s.StringVar(&vs.Ignore, FlagIgnore, "", "Comma separated list of folders to ignore")
+	s.StringVar(&vs.Title, FlagTitle, "", "Title of the generated diagram")
+	s.StringVar(&vs.Output, FlagOutput, "", "Output file path. If omitted, then this will default to standard output")
+	s.BoolVar(&vs.Recursive, FlagRecursive, false, "Walk all directories recursively")
+	s.StringVar(&vs.Format, FlagFormat, FormatPlantUML, "Output format (plantuml, mermaid)")
+	s.BoolVar(&vs.Check, FlagCheck, false, "Compare the generated diagram with the output file instead of writing it, and fail with a unified diff if they differ")
+	s.StringVar(&vs.Render, FlagRender, "", "Render the diagram as an image (svg, png) with a local PlantUML jar file (or plantuml command) instead of writing the source")
+	s.StringVar(&vs.PlantUMLJar, FlagPlantUMLJar, "", "PlantUML jar file path used by --render. If omitted, then this will default to $PLANTUML_JAR, or plantuml command in PATH")
+}
+
+func (fs *FlagSet) Values() FlagValues {
+	return fs.values
+}
+
+func NewERDiagramGenCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "erd",
+		Short: "generate entity-relationship diagram from structs with db, gorm or sql struct tags",
+	}
+
+	fs := FlagSet{set: cmd.PersistentFlags()}
+	fs.InitializeFlags()
+
+	cmd.Run = func(cmd *cobra.Command, args []string) { run(fs.Values(), args) }
+
+	return cmd
+}
+
+func run(flagValues FlagValues, args []string) {
+	dirs, err := directory.FromArgs(args)
+	if err != nil {
+		fmt.Println("usage:\ngodiagramgen erd <DIR>\nDIR Must be a valid directory")
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	ignoredDirectories, err := directory.IgnoredFromList(flagValues.Ignore)
+	if err != nil {
+		fmt.Println("usage:\ngodiagramgen erd [-ignore=<DIRLIST>]\nDIRLIST Must be a valid comma separated list of existing directories")
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	if err := Generate(flagValues, dirs, ignoredDirectories); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+// Generate は dirs の struct tag を持つ struct をテーブルとしたエンティティ関連図を生成し、 flagValues.Output (省略時は標準出力) に書き込む。
+// flagValues.Check の場合は書き込まずに flagValues.Output と比較し、異なる場合は output.StaleError を返す。
+// flagValues.Render を指定した場合は、 PlantUML で画像に変換して書き込む。
+//
+// flagValues.Ignore は参照せず、 ignoredDirectories を除外するディレクトリとする。
+func Generate(flagValues FlagValues, dirs []string, ignoredDirectories []string) error {
+	switch flagValues.Format {
+	case FormatPlantUML, FormatMermaid:
+	default:
+		return fmt.Errorf("unsupported format %s", flagValues.Format)
+	}
+	if flagValues.Render != "" {
+		if flagValues.Format != FormatPlantUML {
+			return fmt.Errorf("--%s is not supported for %s format", FlagRender, flagValues.Format)
+		}
+		if err := output.ValidateImageFormat(flagValues.Render, flagValues.Check); err != nil {
+			return err
+		}
+	}
+
+	cd, err := class.NewDiagram(dirs, ignoredDirectories, flagValues.Recursive, &renderer.RenderingOptions{
+		Title: flagValues.Title,
+	})
+	if err != nil {
+		return err
+	}
+
+	var rendered string
+	if flagValues.Format == FormatMermaid {
+		rendered = emitter.NewMermaidERDEmitter().Emit(cd.Schema())
+	} else {
+		rendered = emitter.NewPlantUMLERDEmitter().Emit(cd.Schema())
+	}
+	if flagValues.Render != "" {
+		return output.WriteImage(flagValues.Output, output.LanguagePlantUML, rendered, output.ImageOptions{
+			Format:      flagValues.Render,
+			PlantUMLJar: flagValues.PlantUMLJar,
+		})
+	}
+	if flagValues.Check {
+		return output.Check(flagValues.Output, rendered)
+	}
+	return output.Write(flagValues.Output, rendered)
+}
@@ -0,0 +1,136 @@
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/keisuke-m123/godiagramgen/cmd/godiagramgen/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	FlagConfig = "config"
+)
+
+type FlagValues struct {
+	Config string
+}
+
+type FlagSet struct {
+	set    *pflag.FlagSet
+	values FlagValues
+}
+
+func (fs *FlagSet) InitializeFlags() {
+	s := fs.set
+	vs := &fs.values
+	s.StringVar(&vs.Config, FlagConfig, DefaultConfigFile, "Config file path declaring the diagrams to generate")
+}
+
+func (fs *FlagSet) Values() FlagValues {
+	return fs.values
+}
+
+func NewGenerateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate [NAME...]",
+		Short: "generate all diagrams (or the named ones) declared in the config file",
+	}
+
+	fs := FlagSet{set: cmd.PersistentFlags()}
+	fs.InitializeFlags()
+
+	cmd.Run = func(cmd *cobra.Command, args []string) { run(fs.Values(), args) }
+
+	return cmd
+}
+
+func NewCheckCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check [NAME...]",
+		Short: "check that all diagrams (or the named ones) declared in the config file are up to date",
+	}
+
+	fs := FlagSet{set: cmd.PersistentFlags()}
+	fs.InitializeFlags()
+
+	cmd.Run = func(cmd *cobra.Command, args []string) { runCheck(fs.Values(), args) }
+
+	return cmd
+}
+
+func run(flagValues FlagValues, args []string) {
+	baseDir, diagrams := loadDiagrams(flagValues, args)
+	for _, d := range diagrams {
+		if err := d.Generate(baseDir); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to generate diagram %s: %s\n", d.Name, err.Error())
+			os.Exit(1)
+		}
+	}
+}
+
+// runCheck は全ての図を比較し、古くなった図の unified diff を全て出力してから終了する。
+func runCheck(flagValues FlagValues, args []string) {
+	baseDir, diagrams := loadDiagrams(flagValues, args)
+	if code := checkDiagrams(baseDir, diagrams, os.Stdout, os.Stderr); code != 0 {
+		os.Exit(code)
+	}
+}
+
+// checkDiagrams は diagrams を比較し、古くなった図の unified diff を stdout に、失敗した図と比較しなかった図を stderr に出力する。
+// 全ての図が最新の場合は 0 、それ以外の場合は 1 を終了コードとして返す。
+func checkDiagrams(baseDir string, diagrams []*DiagramConfig, stdout io.Writer, stderr io.Writer) int {
+	var staleNames []string
+	for _, d := range diagrams {
+		err := d.Check(baseDir)
+		var staleErr *output.StaleError
+		switch {
+		case errors.As(err, &staleErr):
+			_, _ = fmt.Fprint(stdout, staleErr.Diff)
+			staleNames = append(staleNames, d.Name)
+		case errors.Is(err, ErrRenderNotChecked):
+			_, _ = fmt.Fprintf(stderr, "skipped diagram %s: %s\n", d.Name, err.Error())
+		case err != nil:
+			_, _ = fmt.Fprintf(stderr, "failed to check diagram %s: %s\n", d.Name, err.Error())
+			return 1
+		}
+	}
+	if len(staleNames) > 0 {
+		_, _ = fmt.Fprintf(stderr, "stale diagrams: %s\n", strings.Join(staleNames, ", "))
+		return 1
+	}
+	return 0
+}
+
+// loadDiagrams は設定ファイルを読み込み、 args で指定された名前の図(省略時は全ての図)を返す。
+func loadDiagrams(flagValues FlagValues, args []string) (string, []*DiagramConfig) {
+	config, err := LoadConfig(flagValues.Config)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	baseDir, err := filepath.Abs(filepath.Dir(flagValues.Config))
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	if len(args) == 0 {
+		return baseDir, config.Diagrams
+	}
+	var diagrams []*DiagramConfig
+	for _, name := range args {
+		d, ok := config.Diagram(name)
+		if !ok {
+			_, _ = fmt.Fprintf(os.Stderr, "diagram %s is not declared in %s\n", name, flagValues.Config)
+			os.Exit(1)
+		}
+		diagrams = append(diagrams, d)
+	}
+	return baseDir, diagrams
+}
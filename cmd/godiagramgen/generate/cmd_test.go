@@ -0,0 +1,85 @@
+package generate
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckDiagrams(t *testing.T) {
+	want, err := ioutil.ReadFile("../../../testingsupport/implements.txt")
+	if err != nil {
+		t.Fatalf("failed to read want file: %s", err)
+	}
+
+	tests := []struct {
+		name       string
+		current    string
+		diagram    DiagramConfig
+		wantCode   int
+		wantStdout []string
+		wantStderr []string
+	}{
+		{
+			name:     "UpToDate",
+			current:  string(want),
+			diagram:  DiagramConfig{Name: "implements", Command: CommandImplements, Inputs: []string{"testingsupport/implements"}, Recursive: boolPtr(true)},
+			wantCode: 0,
+		},
+		{
+			name:       "Stale",
+			current:    strings.Replace(string(want), "Celsius", "Fahrenheit", 1),
+			diagram:    DiagramConfig{Name: "implements", Command: CommandImplements, Inputs: []string{"testingsupport/implements"}, Recursive: boolPtr(true)},
+			wantCode:   1,
+			wantStdout: []string{"-    github.com/keisuke-m123/godiagramgen/testingsupport/implements.Fahrenheit\n", "+    github.com/keisuke-m123/godiagramgen/testingsupport/implements.Celsius\n"},
+			wantStderr: []string{"stale diagrams: implements\n"},
+		},
+		{
+			name:       "Render",
+			diagram:    DiagramConfig{Name: "class-svg", Command: CommandClass, Inputs: []string{"testingsupport/implements"}, Render: "svg"},
+			wantCode:   0,
+			wantStderr: []string{"skipped diagram class-svg: "},
+		},
+		{
+			name:       "Failure",
+			diagram:    DiagramConfig{Name: "not-found", Command: CommandImplements, Inputs: []string{"testingsupport/notfound"}},
+			wantCode:   1,
+			wantStderr: []string{"failed to check diagram not-found: could not find directory "},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "implements.txt")
+			if err := ioutil.WriteFile(output, []byte(test.current), 0644); err != nil {
+				t.Fatalf("failed to write file: %s", err)
+			}
+			d := test.diagram
+			d.Output = output
+
+			var stdout, stderr bytes.Buffer
+			if code := checkDiagrams("../../..", []*DiagramConfig{&d}, &stdout, &stderr); code != test.wantCode {
+				t.Errorf("checkDiagrams() = %d, want %d (stderr: %s)", code, test.wantCode, stderr.String())
+			}
+			if len(test.wantStdout) == 0 && stdout.Len() > 0 {
+				t.Errorf("stdout = %s, want empty", stdout.String())
+			}
+			for _, want := range test.wantStdout {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("stdout does not contain %q:\n%s", want, stdout.String())
+				}
+			}
+			for _, want := range test.wantStderr {
+				if !strings.Contains(stderr.String(), want) {
+					t.Errorf("stderr does not contain %q:\n%s", want, stderr.String())
+				}
+			}
+		})
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,343 @@
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/keisuke-m123/godiagramgen/cmd/godiagramgen/classdiagram"
+	"github.com/keisuke-m123/godiagramgen/cmd/godiagramgen/erdiagram"
+	"github.com/keisuke-m123/godiagramgen/cmd/godiagramgen/implementsreport"
+	"github.com/keisuke-m123/godiagramgen/cmd/godiagramgen/pkgdiagram"
+	"github.com/keisuke-m123/godiagramgen/cmd/godiagramgen/pkgmetrics"
+	"github.com/keisuke-m123/godiagramgen/cmd/godiagramgen/sequencediagram"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultConfigFile は generate コマンドが既定で読み込む設定ファイルのパス。
+const DefaultConfigFile = ".godiagramgen.yaml"
+
+const (
+	CommandClass      = "class"
+	CommandPackage    = "package"
+	CommandMetrics    = "metrics"
+	CommandSequence   = "sequence"
+	CommandImplements = "implements"
+	CommandERD        = "erd"
+)
+
+// ErrRenderNotChecked は render を指定した図を比較しなかったことを表す。
+var ErrRenderNotChecked = errors.New("rendered images are not checked because they depend on the version of the rendering tool")
+
+type (
+	// Config は設定ファイルに宣言された図の一覧を表す。
+	Config struct {
+		Diagrams []*DiagramConfig `yaml:"diagrams"`
+	}
+
+	// DiagramConfig は一つの図を生成するための設定を表す。
+	//
+	// キーは class, package, metrics, sequence, implements または erd コマンドのフラグ名と同一とし、カンマ区切りのフラグはリストで指定する。
+	// inputs, ignore, output, plantuml-jar のパスは設定ファイルのディレクトリからの相対パスとする。
+	// recursive は package と metrics コマンドのみ、フラグと同様に省略した場合は true とする。
+	// depth は class コマンドではフォーカスから辿る深さ、 package と metrics コマンドではパッケージをまとめる深さ、
+	// sequence コマンドでは起点から辿る呼び出しのネストの深さとする。
+	DiagramConfig struct {
+		Name    string   `yaml:"name"`
+		Command string   `yaml:"command"`
+		Inputs  []string `yaml:"inputs"`
+		Ignore  []string `yaml:"ignore"`
+		Output  string   `yaml:"output"`
+		Format  string   `yaml:"format"`
+		Theme   string   `yaml:"theme"`
+		Title   string   `yaml:"title"`
+		Notes   []string `yaml:"notes"`
+
+		Recursive                *bool    `yaml:"recursive"`
+		RenderExternalPackages   bool     `yaml:"render-external-packages"`
+		ShortPackageNames        bool     `yaml:"short-package-names"`
+		RenderFunctions          bool     `yaml:"render-functions"`
+		RenderConstants          bool     `yaml:"render-constants"`
+		RenderVariables          bool     `yaml:"render-variables"`
+		RenderMethodDependencies bool     `yaml:"render-method-dependencies"`
+		Focus                    string   `yaml:"focus"`
+		Depth                    *int     `yaml:"depth"`
+		FocusDirection           string   `yaml:"focus-direction"`
+		IncludePackages          []string `yaml:"include-packages"`
+		ExcludePackages          []string `yaml:"exclude-packages"`
+		IncludeTypes             []string `yaml:"include-types"`
+		ExcludeTypes             []string `yaml:"exclude-types"`
+		Kinds                    []string `yaml:"kinds"`
+		ExportedOnly             bool     `yaml:"exported-only"`
+		APISurface               bool     `yaml:"api-surface"`
+		RenderTags               bool     `yaml:"render-tags"`
+		TagKeys                  []string `yaml:"tag-keys"`
+		RenderDocs               bool     `yaml:"render-docs"`
+		HideStdlib               bool     `yaml:"hide-stdlib"`
+		HideThirdParty           bool     `yaml:"hide-third-party"`
+		CollapseThirdParty       bool     `yaml:"collapse-third-party"`
+		DetectCycles             bool     `yaml:"detect-cycles"`
+		ForbidImports            []string `yaml:"forbid-imports"`
+		ColorBy                  string   `yaml:"color-by"`
+		Entry                    string   `yaml:"entry"`
+		Interface                string   `yaml:"interface"`
+		Render                   string   `yaml:"render"`
+		PlantUMLJar              string   `yaml:"plantuml-jar"`
+	}
+)
+
+// LoadConfig は path の設定ファイルを読み込み、検証する。
+func LoadConfig(path string) (*Config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer file.Close()
+
+	config, err := parseConfig(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config file %s: %w", path, err)
+	}
+	return config, nil
+}
+
+func parseConfig(r io.Reader) (*Config, error) {
+	decoder := yaml.NewDecoder(r)
+	// フラグ名の誤りに気付けるよう、未知のキーはエラーとする。
+	decoder.KnownFields(true)
+
+	var config Config
+	if err := decoder.Decode(&config); err != nil {
+		return nil, err
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func (c *Config) validate() error {
+	if len(c.Diagrams) == 0 {
+		return errors.New("no diagrams declared")
+	}
+	nameSet := make(map[string]struct{})
+	for i, d := range c.Diagrams {
+		if d.Name == "" {
+			return fmt.Errorf("diagrams[%d]: name is required", i)
+		}
+		if _, ok := nameSet[d.Name]; ok {
+			return fmt.Errorf("diagram %s: name is duplicated", d.Name)
+		}
+		nameSet[d.Name] = struct{}{}
+
+		if d.Command != CommandClass && d.Command != CommandPackage && d.Command != CommandMetrics &&
+			d.Command != CommandSequence && d.Command != CommandImplements && d.Command != CommandERD {
+			return fmt.Errorf("diagram %s: unsupported command %s", d.Name, d.Command)
+		}
+		if len(d.Inputs) == 0 {
+			return fmt.Errorf("diagram %s: inputs is required", d.Name)
+		}
+		if d.Output == "" {
+			return fmt.Errorf("diagram %s: output is required", d.Name)
+		}
+		if d.Render != "" && (d.Command == CommandMetrics || d.Command == CommandImplements) {
+			return fmt.Errorf("diagram %s: render is not supported for %s command", d.Name, d.Command)
+		}
+	}
+	return nil
+}
+
+// Diagram は name の図の設定を返す。
+func (c *Config) Diagram(name string) (*DiagramConfig, bool) {
+	for _, d := range c.Diagrams {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	return nil, false
+}
+
+// Generate は baseDir を基準にパスを解決し、図を生成する。
+func (d *DiagramConfig) Generate(baseDir string) error {
+	return d.run(baseDir, false)
+}
+
+// Check は baseDir を基準にパスを解決し、生成した図を書き込み済みの図と比較する。
+// 異なる場合は output.StaleError を返す。
+//
+// render を指定した図は、画像の内容が変換するツールのバージョンによって変わるため比較せず、 ErrRenderNotChecked を返す。
+func (d *DiagramConfig) Check(baseDir string) error {
+	if d.Render != "" {
+		return ErrRenderNotChecked
+	}
+	return d.run(baseDir, true)
+}
+
+func (d *DiagramConfig) run(baseDir string, check bool) error {
+	dirs, err := resolveDirectories(baseDir, d.Inputs)
+	if err != nil {
+		return err
+	}
+	ignoredDirectories := resolvePaths(baseDir, d.Ignore)
+	output := resolvePath(baseDir, d.Output)
+	plantUMLJar := ""
+	if d.PlantUMLJar != "" {
+		plantUMLJar = resolvePath(baseDir, d.PlantUMLJar)
+	}
+
+	switch d.Command {
+	case CommandPackage:
+		depth := 0
+		if d.Depth != nil {
+			depth = *d.Depth
+		}
+		return pkgdiagram.Generate(pkgdiagram.FlagValues{
+			Output:                 output,
+			Theme:                  d.Theme,
+			Recursive:              withDefaultBool(d.Recursive, true),
+			Format:                 withDefault(d.Format, pkgdiagram.FormatPlantUML),
+			Check:                  check,
+			Depth:                  depth,
+			RenderExternalPackages: d.RenderExternalPackages,
+			HideStdlib:             d.HideStdlib,
+			HideThirdParty:         d.HideThirdParty,
+			CollapseThirdParty:     d.CollapseThirdParty,
+			DetectCycles:           d.DetectCycles,
+			ForbidImports:          strings.Join(d.ForbidImports, ","),
+			ColorBy:                d.ColorBy,
+			Render:                 d.Render,
+			PlantUMLJar:            plantUMLJar,
+		}, dirs, ignoredDirectories)
+	case CommandMetrics:
+		depth := 0
+		if d.Depth != nil {
+			depth = *d.Depth
+		}
+		return pkgmetrics.Generate(pkgmetrics.FlagValues{
+			Output:    output,
+			Recursive: withDefaultBool(d.Recursive, true),
+			Format:    withDefault(d.Format, pkgmetrics.FormatTable),
+			Check:     check,
+			Depth:     depth,
+		}, dirs, ignoredDirectories)
+	case CommandSequence:
+		depth := sequencediagram.DefaultDepth
+		if d.Depth != nil {
+			depth = *d.Depth
+		}
+		return sequencediagram.Generate(sequencediagram.FlagValues{
+			Output:          output,
+			Recursive:       withDefaultBool(d.Recursive, false),
+			Check:           check,
+			Entry:           d.Entry,
+			Depth:           depth,
+			IncludePackages: strings.Join(d.IncludePackages, ","),
+			ExcludePackages: strings.Join(d.ExcludePackages, ","),
+			Render:          d.Render,
+			PlantUMLJar:     plantUMLJar,
+		}, dirs, ignoredDirectories)
+	case CommandImplements:
+		return implementsreport.Generate(implementsreport.FlagValues{
+			Output:    output,
+			Recursive: withDefaultBool(d.Recursive, false),
+			Format:    withDefault(d.Format, implementsreport.FormatText),
+			Interface: d.Interface,
+			Check:     check,
+		}, dirs, ignoredDirectories)
+	case CommandERD:
+		return erdiagram.Generate(erdiagram.FlagValues{
+			Title:     d.Title,
+			Output:    output,
+			Recursive: withDefaultBool(d.Recursive, false),
+			Format:    withDefault(d.Format, erdiagram.FormatPlantUML),
+			Check:     check,
+
+			Render:      d.Render,
+			PlantUMLJar: plantUMLJar,
+		}, dirs, ignoredDirectories)
+	default:
+		depth := 1
+		if d.Depth != nil {
+			depth = *d.Depth
+		}
+		return classdiagram.Generate(classdiagram.FlagValues{
+			Title:                  d.Title,
+			Notes:                  d.Notes,
+			Output:                 output,
+			Theme:                  d.Theme,
+			Recursive:              withDefaultBool(d.Recursive, false),
+			RenderExternalPackages: d.RenderExternalPackages,
+			Format:                 withDefault(d.Format, classdiagram.FormatPlantUML),
+			ShortPackageNames:      d.ShortPackageNames,
+			RenderFunctions:        d.RenderFunctions,
+			RenderConstants:        d.RenderConstants,
+			RenderVariables:        d.RenderVariables,
+			RenderMethodDeps:       d.RenderMethodDependencies,
+			Focus:                  d.Focus,
+			Depth:                  depth,
+			FocusDirection:         withDefault(d.FocusDirection, classdiagram.FocusDirectionBoth),
+			IncludePackages:        strings.Join(d.IncludePackages, ","),
+			ExcludePackages:        strings.Join(d.ExcludePackages, ","),
+			IncludeTypes:           strings.Join(d.IncludeTypes, ","),
+			ExcludeTypes:           strings.Join(d.ExcludeTypes, ","),
+			Kinds:                  strings.Join(d.Kinds, ","),
+			ExportedOnly:           d.ExportedOnly,
+			APISurface:             d.APISurface,
+			RenderTags:             d.RenderTags,
+			TagKeys:                strings.Join(d.TagKeys, ","),
+			RenderDocs:             d.RenderDocs,
+			Check:                  check,
+			Render:                 d.Render,
+			PlantUMLJar:            plantUMLJar,
+		}, dirs, ignoredDirectories)
+	}
+}
+
+func resolveDirectories(baseDir string, paths []string) ([]string, error) {
+	dirs := resolvePaths(baseDir, paths)
+	for _, dir := range dirs {
+		fi, err := os.Stat(dir)
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("could not find directory %s", dir)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !fi.Mode().IsDir() {
+			return nil, fmt.Errorf("%s is not a directory", dir)
+		}
+	}
+	return dirs, nil
+}
+
+func resolvePaths(baseDir string, paths []string) []string {
+	var resolved []string
+	for _, p := range paths {
+		resolved = append(resolved, resolvePath(baseDir, p))
+	}
+	return resolved
+}
+
+func resolvePath(baseDir string, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(baseDir, path)
+}
+
+func withDefault(value string, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func withDefaultBool(value *bool, defaultValue bool) bool {
+	if value == nil {
+		return defaultValue
+	}
+	return *value
+}
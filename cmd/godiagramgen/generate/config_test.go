@@ -0,0 +1,238 @@
+package generate
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		yaml      string
+		wantErr   string
+		wantNames []string
+	}{
+		{
+			name: "Valid",
+			yaml: `
+diagrams:
+  - name: class
+    command: class
+    inputs: [.]
+    output: class.puml
+  - name: package
+    command: package
+    inputs: [.]
+    output: package.puml
+    render: svg
+`,
+			wantNames: []string{"class", "package"},
+		},
+		{
+			name: "UnknownKey",
+			yaml: `
+diagrams:
+  - name: class
+    command: class
+    inputs: [.]
+    output: class.puml
+    recursve: true
+`,
+			wantErr: "field recursve not found",
+		},
+		{
+			name:    "NoDiagrams",
+			yaml:    "diagrams: []\n",
+			wantErr: "no diagrams declared",
+		},
+		{
+			name: "NameRequired",
+			yaml: `
+diagrams:
+  - command: class
+    inputs: [.]
+    output: class.puml
+`,
+			wantErr: "diagrams[0]: name is required",
+		},
+		{
+			name: "DuplicatedName",
+			yaml: `
+diagrams:
+  - name: class
+    command: class
+    inputs: [.]
+    output: class.puml
+  - name: class
+    command: package
+    inputs: [.]
+    output: package.puml
+`,
+			wantErr: "diagram class: name is duplicated",
+		},
+		{
+			name: "UnsupportedCommand",
+			yaml: `
+diagrams:
+  - name: class
+    command: diff
+    inputs: [.]
+    output: class.puml
+`,
+			wantErr: "diagram class: unsupported command diff",
+		},
+		{
+			name: "InputsRequired",
+			yaml: `
+diagrams:
+  - name: class
+    command: class
+    output: class.puml
+`,
+			wantErr: "diagram class: inputs is required",
+		},
+		{
+			name: "OutputRequired",
+			yaml: `
+diagrams:
+  - name: class
+    command: class
+    inputs: [.]
+`,
+			wantErr: "diagram class: output is required",
+		},
+		{
+			name: "RenderMetrics",
+			yaml: `
+diagrams:
+  - name: metrics
+    command: metrics
+    inputs: [.]
+    output: metrics.csv
+    render: svg
+`,
+			wantErr: "diagram metrics: render is not supported for metrics command",
+		},
+		{
+			name: "RenderImplements",
+			yaml: `
+diagrams:
+  - name: implements
+    command: implements
+    inputs: [.]
+    output: implements.txt
+    render: png
+`,
+			wantErr: "diagram implements: render is not supported for implements command",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, err := parseConfig(strings.NewReader(test.yaml))
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Errorf("parseConfig() error = %v, want %s", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseConfig() error = %v", err)
+			}
+			var names []string
+			for _, d := range config.Diagrams {
+				names = append(names, d.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(test.wantNames, ",") {
+				t.Errorf("names = %v, want %v", names, test.wantNames)
+			}
+		})
+	}
+}
+
+func TestResolvePaths(t *testing.T) {
+	baseDir := filepath.FromSlash("/repo/docs")
+	tests := []struct {
+		name  string
+		paths []string
+		want  []string
+	}{
+		{name: "Current", paths: []string{"."}, want: []string{"/repo/docs"}},
+		{name: "Relative", paths: []string{"./diagrams/class.puml", "../pkg"}, want: []string{"/repo/docs/diagrams/class.puml", "/repo/pkg"}},
+		{name: "Absolute", paths: []string{"/opt/plantuml/../plantuml.jar"}, want: []string{"/opt/plantuml.jar"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := resolvePaths(baseDir, test.paths)
+			if len(got) != len(test.want) {
+				t.Fatalf("resolvePaths() = %v, want %v", got, test.want)
+			}
+			for i := range got {
+				if got[i] != filepath.FromSlash(test.want[i]) {
+					t.Errorf("resolvePaths()[%d] = %s, want %s", i, got[i], filepath.FromSlash(test.want[i]))
+				}
+			}
+		})
+	}
+}
+
+func TestResolveDirectories(t *testing.T) {
+	tests := []struct {
+		name    string
+		inputs  []string
+		wantErr string
+	}{
+		{name: "Directory", inputs: []string{"testingsupport/implements"}},
+		{name: "NotFound", inputs: []string{"testingsupport/notfound"}, wantErr: "could not find directory"},
+		{name: "File", inputs: []string{"testingsupport/implements.txt"}, wantErr: "is not a directory"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dirs, err := resolveDirectories("../../..", test.inputs)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Errorf("resolveDirectories() error = %v, want %s", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveDirectories() error = %v", err)
+			}
+			if want := filepath.Join("../../..", test.inputs[0]); len(dirs) != 1 || dirs[0] != want {
+				t.Errorf("resolveDirectories() = %v, want [%s]", dirs, want)
+			}
+		})
+	}
+}
+
+func TestDiagramConfig_Generate_Notes(t *testing.T) {
+	config, err := parseConfig(strings.NewReader(`
+diagrams:
+  - name: class
+    command: class
+    inputs: [testingsupport/implements]
+    notes: ["Generated by make, do not edit", Example 2]
+    output: class.puml
+`))
+	if err != nil {
+		t.Fatalf("parseConfig() error = %v", err)
+	}
+	d, _ := config.Diagram("class")
+	d.Output = filepath.Join(t.TempDir(), "class.puml")
+
+	if err := d.Generate("../../.."); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	got, err := ioutil.ReadFile(d.Output)
+	if err != nil {
+		t.Fatalf("failed to read output: %s", err)
+	}
+	want := "<b><u>Notes</u></b>\nGenerated by make, do not edit\nExample 2\n"
+	if !strings.Contains(string(got), want) {
+		t.Errorf("output does not contain notes %q:\n%s", want, got)
+	}
+}
@@ -0,0 +1,126 @@
+package implementsreport
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/keisuke-m123/godiagramgen/cmd/godiagramgen/output"
+	"github.com/keisuke-m123/godiagramgen/diagram/class"
+	"github.com/keisuke-m123/godiagramgen/diagram/class/renderer"
+	"github.com/keisuke-m123/godiagramgen/diagram/directory"
+	"github.com/keisuke-m123/godiagramgen/diagram/implementation"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	FlagIgnore    = "ignore"
+	FlagOutput    = "output"
+	FlagRecursive = "recursive"
+	FlagFormat    = "format"
+	FlagInterface = "interface"
+	FlagCheck     = "check"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+type FlagValues struct {
+	Ignore    string
+	Output    string
+	Recursive bool
+	Format    string
+	Interface string
+	Check     bool
+}
+
+type FlagSet struct {
+	set    *pflag.FlagSet
+	values FlagValues
+}
+
+func (fs *FlagSet) InitializeFlags() {
+	s := fs.set
+	vs := &fs.values
+	s.StringVar(&vs.Ignore, FlagIgnore, "", "Comma separated list of folders to ignore")
+	s.StringVar(&vs.Output, FlagOutput, "", "Output file path. If omitted, then this will default to standard output")
+	s.BoolVar(&vs.Recursive, FlagRecursive, false, "Walk all directories recursively")
+	s.StringVar(&vs.Format, FlagFormat, FormatText, "Output format (text, json)")
+	s.StringVar(&vs.Interface, FlagInterface, "", "Report only the interface (pkg.Name, pkg is an import path or a package name)")
+	s.BoolVar(&vs.Check, FlagCheck, false, "Compare the generated report with the output file instead of writing it, and fail with a unified diff if they differ")
+}
+
+func (fs *FlagSet) Values() FlagValues {
+	return fs.values
+}
+
+func NewImplementsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "implements",
+		Short: "report structs and defined types implementing each interface of specified packages and standard interfaces",
+	}
+
+	fs := FlagSet{set: cmd.PersistentFlags()}
+	fs.InitializeFlags()
+
+	cmd.Run = func(cmd *cobra.Command, args []string) { run(fs.Values(), args) }
+
+	return cmd
+}
+
+func run(flagValues FlagValues, args []string) {
+	dirs, err := directory.FromArgs(args)
+	if err != nil {
+		fmt.Println("usage:\ngodiagramgen implements <DIR>\nDIR Must be a valid directory")
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	ignoredDirectories, err := directory.IgnoredFromList(flagValues.Ignore)
+	if err != nil {
+		fmt.Println("usage:\ngodiagramgen implements [-ignore=<DIRLIST>]\nDIRLIST Must be a valid comma separated list of existing directories")
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	if err := Generate(flagValues, dirs, ignoredDirectories); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+// Generate は dirs の interface 毎に実装する型を列挙し、 flagValues.Output (省略時は標準出力) に書き込む。
+// flagValues.Check の場合は書き込まずに flagValues.Output と比較し、異なる場合は output.StaleError を返す。
+//
+// flagValues.Ignore は参照せず、 ignoredDirectories を除外するディレクトリとする。
+func Generate(flagValues FlagValues, dirs []string, ignoredDirectories []string) error {
+	var render func([]*implementation.Entry) string
+	switch flagValues.Format {
+	case FormatText:
+		render = implementation.RenderText
+	case FormatJSON:
+		render = implementation.RenderJSON
+	default:
+		return fmt.Errorf("unsupported format %s", flagValues.Format)
+	}
+
+	cd, err := class.NewDiagram(dirs, ignoredDirectories, flagValues.Recursive, &renderer.RenderingOptions{})
+	if err != nil {
+		return err
+	}
+
+	entries := cd.Implementations()
+	if flagValues.Interface != "" {
+		entries, err = implementation.Filter(entries, flagValues.Interface)
+		if err != nil {
+			return err
+		}
+	}
+
+	rendered := render(entries)
+	if flagValues.Check {
+		return output.Check(flagValues.Output, rendered)
+	}
+	return output.Write(flagValues.Output, rendered)
+}
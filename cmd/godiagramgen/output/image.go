@@ -0,0 +1,138 @@
+package output
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const (
+	ImageFormatSVG = "svg"
+	ImageFormatPNG = "png"
+)
+
+// EnvPlantUMLJar は ImageOptions.PlantUMLJar を省略した場合に参照する、 PlantUML の jar ファイルのパスの環境変数。
+const EnvPlantUMLJar = "PLANTUML_JAR"
+
+const (
+	// LanguagePlantUML は PlantUML の jar ファイル(または plantuml コマンド)で画像に変換する図を表す。
+	LanguagePlantUML Language = iota
+	// LanguageDOT は Graphviz の dot コマンドで画像に変換する図を表す。
+	LanguageDOT
+)
+
+type (
+	// Language は画像に変換する図の記述言語を表す。
+	Language int
+
+	// ImageOptions は図を画像に変換する方法を表す。
+	ImageOptions struct {
+		// Format は画像の形式(svg, png)を表す。
+		Format string
+		// PlantUMLJar は PlantUML の jar ファイルのパスを表す。
+		// 空の場合は環境変数 PLANTUML_JAR を、それも空の場合は PATH の plantuml コマンドを使う。
+		PlantUMLJar string
+	}
+
+	// ToolNotFoundError は図を画像に変換するためのツールが見つからないことを表す。
+	ToolNotFoundError struct {
+		Tool string
+		// Hint はツールを利用できるようにする方法を表す。
+		Hint string
+	}
+)
+
+func (e *ToolNotFoundError) Error() string {
+	return fmt.Sprintf("%s is not found: %s", e.Tool, e.Hint)
+}
+
+// ValidateImageFormat は format が画像の形式として指定できるかを検証する。
+//
+// 画像はツールのバージョンによって内容が変わるため、書き込み済みのファイルとの比較(check)とは併用できない。
+func ValidateImageFormat(format string, check bool) error {
+	switch format {
+	case ImageFormatSVG, ImageFormatPNG:
+	default:
+		return fmt.Errorf("unsupported image format %s (svg, png)", format)
+	}
+	if check {
+		return errors.New("rendering an image cannot be combined with check")
+	}
+	return nil
+}
+
+// WriteImage は language で記述された source をローカルのツールで画像に変換し、 path に書き込む。
+// path が空の場合は標準出力に書き込む。
+func WriteImage(path string, language Language, source string, options ImageOptions) error {
+	image, err := RenderImage(language, source, options)
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		_, err := os.Stdout.Write(image)
+		return err
+	}
+	return ioutil.WriteFile(path, image, 0644)
+}
+
+// RenderImage は language で記述された source をローカルのツールで画像に変換する。
+//
+// ツールが見つからない場合は ToolNotFoundError を返す。
+func RenderImage(language Language, source string, options ImageOptions) ([]byte, error) {
+	if err := ValidateImageFormat(options.Format, false); err != nil {
+		return nil, err
+	}
+	cmd, err := imageCommand(language, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = strings.NewReader(source)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to render %s image with %s: %w\n%s", options.Format, cmd.Path, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+func imageCommand(language Language, options ImageOptions) (*exec.Cmd, error) {
+	if language == LanguageDOT {
+		dot, err := exec.LookPath("dot")
+		if err != nil {
+			return nil, &ToolNotFoundError{Tool: "dot", Hint: "install Graphviz and add dot to PATH"}
+		}
+		return exec.Command(dot, "-T"+options.Format), nil
+	}
+
+	// 日本語を含む図を環境の既定の文字コードによらず変換できるよう、 UTF-8 を指定する。
+	args := []string{"-pipe", "-charset", "UTF-8", "-t" + options.Format}
+	jar := options.PlantUMLJar
+	if jar == "" {
+		jar = os.Getenv(EnvPlantUMLJar)
+	}
+	if jar == "" {
+		plantuml, err := exec.LookPath("plantuml")
+		if err != nil {
+			return nil, &ToolNotFoundError{
+				Tool: "plantuml",
+				Hint: fmt.Sprintf("specify the PlantUML jar file with --plantuml-jar or %s, or add plantuml command to PATH", EnvPlantUMLJar),
+			}
+		}
+		return exec.Command(plantuml, args...), nil
+	}
+
+	if _, err := os.Stat(jar); err != nil {
+		return nil, &ToolNotFoundError{Tool: "PlantUML jar file", Hint: fmt.Sprintf("%s does not exist", jar)}
+	}
+	java, err := exec.LookPath("java")
+	if err != nil {
+		return nil, &ToolNotFoundError{Tool: "java", Hint: "install Java runtime and add java to PATH to run the PlantUML jar file"}
+	}
+	return exec.Command(java, append([]string{"-Djava.awt.headless=true", "-jar", jar}, args...)...), nil
+}
@@ -0,0 +1,166 @@
+package output
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateImageFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		check   bool
+		wantErr string
+	}{
+		{name: "SVG", format: ImageFormatSVG},
+		{name: "PNG", format: ImageFormatPNG},
+		{name: "Unsupported", format: "pdf", wantErr: "unsupported image format pdf"},
+		{name: "Check", format: ImageFormatSVG, check: true, wantErr: "cannot be combined with check"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateImageFormat(test.format, test.check)
+			if test.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateImageFormat() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("ValidateImageFormat() = %v, want %s", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestImageCommand(t *testing.T) {
+	binDir := t.TempDir()
+	for _, name := range []string{"dot", "plantuml", "java"} {
+		if err := ioutil.WriteFile(filepath.Join(binDir, name), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatalf("failed to write %s: %s", name, err)
+		}
+	}
+	jar := filepath.Join(t.TempDir(), "plantuml.jar")
+	if err := ioutil.WriteFile(jar, nil, 0644); err != nil {
+		t.Fatalf("failed to write jar: %s", err)
+	}
+
+	tests := []struct {
+		name     string
+		language Language
+		options  ImageOptions
+		envJar   string
+		wantArgs []string
+	}{
+		{
+			name:     "DOT",
+			language: LanguageDOT,
+			options:  ImageOptions{Format: ImageFormatPNG},
+			wantArgs: []string{filepath.Join(binDir, "dot"), "-Tpng"},
+		},
+		{
+			name:     "PlantUMLCommand",
+			language: LanguagePlantUML,
+			options:  ImageOptions{Format: ImageFormatSVG},
+			wantArgs: []string{filepath.Join(binDir, "plantuml"), "-pipe", "-charset", "UTF-8", "-tsvg"},
+		},
+		{
+			name:     "PlantUMLJar",
+			language: LanguagePlantUML,
+			options:  ImageOptions{Format: ImageFormatSVG, PlantUMLJar: jar},
+			wantArgs: []string{filepath.Join(binDir, "java"), "-Djava.awt.headless=true", "-jar", jar, "-pipe", "-charset", "UTF-8", "-tsvg"},
+		},
+		{
+			name:     "PlantUMLJarFromEnv",
+			language: LanguagePlantUML,
+			options:  ImageOptions{Format: ImageFormatPNG},
+			envJar:   jar,
+			wantArgs: []string{filepath.Join(binDir, "java"), "-Djava.awt.headless=true", "-jar", jar, "-pipe", "-charset", "UTF-8", "-tpng"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("PATH", binDir)
+			t.Setenv(EnvPlantUMLJar, test.envJar)
+
+			cmd, err := imageCommand(test.language, test.options)
+			if err != nil {
+				t.Fatalf("imageCommand() error = %v", err)
+			}
+			if got, want := strings.Join(cmd.Args, " "), strings.Join(test.wantArgs, " "); got != want {
+				t.Errorf("imageCommand() args = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestImageCommand_ToolNotFound(t *testing.T) {
+	jar := filepath.Join(t.TempDir(), "plantuml.jar")
+	if err := ioutil.WriteFile(jar, nil, 0644); err != nil {
+		t.Fatalf("failed to write jar: %s", err)
+	}
+
+	tests := []struct {
+		name     string
+		language Language
+		options  ImageOptions
+		wantTool string
+		wantHint string
+	}{
+		{
+			name:     "DOT",
+			language: LanguageDOT,
+			options:  ImageOptions{Format: ImageFormatSVG},
+			wantTool: "dot",
+			wantHint: "install Graphviz",
+		},
+		{
+			name:     "PlantUMLCommand",
+			language: LanguagePlantUML,
+			options:  ImageOptions{Format: ImageFormatSVG},
+			wantTool: "plantuml",
+			wantHint: "--plantuml-jar or " + EnvPlantUMLJar,
+		},
+		{
+			name:     "PlantUMLJarMissing",
+			language: LanguagePlantUML,
+			options:  ImageOptions{Format: ImageFormatSVG, PlantUMLJar: filepath.Join("notfound", "plantuml.jar")},
+			wantTool: "PlantUML jar file",
+			wantHint: filepath.Join("notfound", "plantuml.jar") + " does not exist",
+		},
+		{
+			name:     "Java",
+			language: LanguagePlantUML,
+			options:  ImageOptions{Format: ImageFormatSVG, PlantUMLJar: jar},
+			wantTool: "java",
+			wantHint: "install Java runtime",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("PATH", t.TempDir())
+			t.Setenv(EnvPlantUMLJar, "")
+
+			_, err := imageCommand(test.language, test.options)
+			var notFoundErr *ToolNotFoundError
+			if !errors.As(err, &notFoundErr) {
+				t.Fatalf("imageCommand() error = %v, want ToolNotFoundError", err)
+			}
+			if notFoundErr.Tool != test.wantTool {
+				t.Errorf("ToolNotFoundError.Tool = %s, want %s", notFoundErr.Tool, test.wantTool)
+			}
+			if !strings.Contains(notFoundErr.Hint, test.wantHint) {
+				t.Errorf("ToolNotFoundError.Hint = %s, want to contain %s", notFoundErr.Hint, test.wantHint)
+			}
+			if !strings.HasPrefix(notFoundErr.Error(), test.wantTool+" is not found: ") {
+				t.Errorf("ToolNotFoundError.Error() = %s", notFoundErr.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Package output は生成した図をファイルに書き込む、または書き込み済みのファイルと比較する。
+package output
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+type (
+	// StaleError は書き込み済みのファイルが生成した図と異なることを表す。
+	StaleError struct {
+		Path string
+		// Diff は書き込み済みのファイルから生成した図への unified diff を表す。
+		Diff string
+	}
+)
+
+func (e *StaleError) Error() string {
+	return fmt.Sprintf("%s is stale:\n%s", e.Path, e.Diff)
+}
+
+// Write は rendered を path に書き込む。 path が空の場合は標準出力に書き込む。
+func Write(path string, rendered string) error {
+	if path == "" {
+		_, err := fmt.Fprint(os.Stdout, rendered)
+		return err
+	}
+	return ioutil.WriteFile(path, []byte(rendered), 0644)
+}
+
+// Check は path のファイルが rendered と一致するかを検証し、異なる場合は StaleError を返す。
+//
+// path のファイルが存在しない場合は空のファイルとして比較する。
+func Check(path string, rendered string) error {
+	if path == "" {
+		return errors.New("output file path is required to check")
+	}
+	current, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if string(current) == rendered {
+		return nil
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(current)),
+		B:        difflib.SplitLines(rendered),
+		FromFile: path,
+		ToFile:   path + " (generated)",
+		Context:  3,
+	})
+	if err != nil {
+		return err
+	}
+	return &StaleError{Path: path, Diff: diff}
+}
@@ -0,0 +1,84 @@
+package output
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		current   *string
+		rendered  string
+		wantStale bool
+		wantDiff  []string
+	}{
+		{
+			name:     "UpToDate",
+			current:  stringPtr("a\nb\n"),
+			rendered: "a\nb\n",
+		},
+		{
+			name:      "Stale",
+			current:   stringPtr("a\nb\n"),
+			rendered:  "a\nc\n",
+			wantStale: true,
+			wantDiff:  []string{"--- ", "+++ ", " a\n", "-b\n", "+c\n"},
+		},
+		{
+			name:      "NotExist",
+			rendered:  "a\n",
+			wantStale: true,
+			wantDiff:  []string{"+a\n"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "diagram.puml")
+			if test.current != nil {
+				if err := ioutil.WriteFile(path, []byte(*test.current), 0644); err != nil {
+					t.Fatalf("failed to write file: %s", err)
+				}
+			}
+
+			err := Check(path, test.rendered)
+			if !test.wantStale {
+				if err != nil {
+					t.Errorf("Check() = %v, want nil", err)
+				}
+				return
+			}
+			var staleErr *StaleError
+			if !errors.As(err, &staleErr) {
+				t.Fatalf("Check() = %v, want StaleError", err)
+			}
+			if staleErr.Path != path {
+				t.Errorf("StaleError.Path = %s, want %s", staleErr.Path, path)
+			}
+			for _, want := range test.wantDiff {
+				if !strings.Contains(staleErr.Diff, want) {
+					t.Errorf("StaleError.Diff does not contain %q:\n%s", want, staleErr.Diff)
+				}
+			}
+			if !strings.HasPrefix(staleErr.Error(), path+" is stale:\n") {
+				t.Errorf("StaleError.Error() = %s, want prefix %s is stale", staleErr.Error(), path)
+			}
+		})
+	}
+}
+
+func TestCheck_WithoutPath(t *testing.T) {
+	err := Check("", "a\n")
+	var staleErr *StaleError
+	if err == nil || errors.As(err, &staleErr) {
+		t.Errorf("Check() = %v, want error other than StaleError", err)
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
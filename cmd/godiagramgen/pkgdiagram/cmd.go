@@ -1,22 +1,43 @@
 package pkgdiagram
 
 import (
-	"errors"
 	"fmt"
-	"io"
 	"os"
-	"path/filepath"
-	"strings"
 
+	"github.com/keisuke-m123/godiagramgen/cmd/godiagramgen/output"
+	"github.com/keisuke-m123/godiagramgen/diagram/architecture"
+	"github.com/keisuke-m123/godiagramgen/diagram/directory"
+	"github.com/keisuke-m123/godiagramgen/diagram/emitter"
+	"github.com/keisuke-m123/godiagramgen/diagram/metrics"
 	"github.com/keisuke-m123/godiagramgen/diagram/pkg"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
 const (
-	FlagIgnore = "ignore"
-	FlagOutput = "output"
-	FlagTheme  = "theme"
+	FlagIgnore    = "ignore"
+	FlagOutput    = "output"
+	FlagTheme     = "theme"
+	FlagRecursive = "recursive"
+	FlagFormat    = "format"
+	FlagCheck     = "check"
+	FlagDepth     = "depth"
+
+	FlagRender      = "render"
+	FlagPlantUMLJar = "plantuml-jar"
+
+	FlagRenderExternalPackages = "render-external-packages"
+	FlagHideStdlib             = "hide-stdlib"
+	FlagHideThirdParty         = "hide-third-party"
+	FlagCollapseThirdParty     = "collapse-third-party"
+	FlagDetectCycles           = "detect-cycles"
+	FlagForbidImports          = "forbid-imports"
+	FlagColorBy                = "color-by"
+)
+
+const (
+	FormatPlantUML = "plantuml"
+	FormatDOT      = "dot"
 )
 
 type FlagValues struct {
@@ -24,6 +45,20 @@ type FlagValues struct {
 	Output    string
 	Theme     string
 	Recursive bool
+	Format    string
+	Check     bool
+	Depth     int
+
+	Render      string
+	PlantUMLJar string
+
+	RenderExternalPackages bool
+	HideStdlib             bool
+	HideThirdParty         bool
+	CollapseThirdParty     bool
+	DetectCycles           bool
+	ForbidImports          string
+	ColorBy                string
 }
 
 type FlagSet struct {
@@ -37,6 +72,19 @@ func (fs *FlagSet) InitializeFlags() {
 	s.StringVar(&vs.Ignore, FlagIgnore, "", "Comma separated list of folders to ignore")
 	s.StringVar(&vs.Output, FlagOutput, "", "Output file path. If omitted, then this will default to standard output")
 	s.StringVar(&vs.Theme, FlagTheme, "", "Change theme")
+	s.BoolVar(&vs.Recursive, FlagRecursive, true, "Walk all directories recursively (use --recursive=false to render only the given directories)")
+	s.StringVar(&vs.Format, FlagFormat, FormatPlantUML, "Output format (plantuml, dot)")
+	s.IntVar(&vs.Depth, FlagDepth, 0, "Collapse packages deeper than this number of path segments below the common path of all packages into their ancestor (0 renders all packages)")
+	s.BoolVar(&vs.RenderExternalPackages, FlagRenderExternalPackages, false, "Render imported packages outside of the specified directories (standard library and other modules) with distinct styles")
+	s.BoolVar(&vs.HideStdlib, FlagHideStdlib, false, "Do not render standard library packages")
+	s.BoolVar(&vs.HideThirdParty, FlagHideThirdParty, false, "Do not render packages of other modules")
+	s.BoolVar(&vs.CollapseThirdParty, FlagCollapseThirdParty, false, "Collapse packages of other modules into their module root required in go.mod")
+	s.BoolVar(&vs.DetectCycles, FlagDetectCycles, false, "Fail if packages collapsed by --depth import each other in a cycle, and highlight the imports in the cycles in red (without --depth no cycles can exist, as Go forbids import cycles. Imports of _test.go files, including external _test packages, are not loaded, so cycles through test-only imports are not detected)")
+	s.StringVar(&vs.ForbidImports, FlagForbidImports, "", "Comma separated list of rules FROM->TO of import path patterns (glob, or regexp prefixed with re:) forbidding FROM packages to import TO packages. Fail if any import violates them, and highlight the imports in red")
+	s.StringVar(&vs.ColorBy, FlagColorBy, "", "Color packages of the module by the metric (afferent, efferent, instability, abstractness, distance) from green (low) to red (high)")
+	s.BoolVar(&vs.Check, FlagCheck, false, "Compare the generated diagram with the output file instead of writing it, and fail with a unified diff if they differ")
+	s.StringVar(&vs.Render, FlagRender, "", "Render the diagram as an image (svg, png) instead of writing the source, with a local PlantUML jar file (or plantuml command) for plantuml format, or Graphviz dot for dot format")
+	s.StringVar(&vs.PlantUMLJar, FlagPlantUMLJar, "", "PlantUML jar file path used by --render. If omitted, then this will default to $PLANTUML_JAR, or plantuml command in PATH")
 }
 
 func (fs *FlagSet) Values() FlagValues {
@@ -58,73 +106,95 @@ func NewPackageDiagramGenCommand() *cobra.Command {
 }
 
 func run(flagValues FlagValues, args []string) {
-	dirs, err := getDirectories(args)
+	dirs, err := directory.FromArgs(args)
 	if err != nil {
 		fmt.Println("usage:\ngoplantuml <DIR>\nDIR Must be a valid directory")
 		_, _ = fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
-	ignoredDirectories, err := getIgnoredDirectories(flagValues.Ignore)
+	ignoredDirectories, err := directory.IgnoredFromList(flagValues.Ignore)
 	if err != nil {
 		fmt.Println("usage:\ngoplantuml [-ignore=<DIRLIST>]\nDIRLIST Must be a valid comma separated list of existing directories")
 		_, _ = fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
 
-	cd, err := pkg.NewDiagram(dirs, ignoredDirectories, flagValues.Theme)
-	if err != nil {
+	if err := Generate(flagValues, dirs, ignoredDirectories); err != nil {
 		_, _ = fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
-
-	rendered := cd.Render()
-	var writer io.Writer
-	if flagValues.Output != "" {
-		writer, err = os.Create(flagValues.Output)
-		if err != nil {
-			_, _ = fmt.Fprintln(os.Stderr, err.Error())
-		}
-	} else {
-		writer = os.Stdout
-	}
-	_, _ = fmt.Fprint(writer, rendered)
 }
 
-func getDirectories(args []string) ([]string, error) {
-	if len(args) < 1 {
-		return nil, errors.New("DIR missing")
+// Generate は dirs のパッケージ図を flagValues に従って生成し、 flagValues.Output (省略時は標準出力) に書き込む。
+// flagValues.Check の場合は書き込まずに flagValues.Output と比較し、異なる場合は output.StaleError を返す。
+// flagValues.Render を指定した場合は、 flagValues.Format に応じて PlantUML または Graphviz で画像に変換して書き込む。
+// import の循環または規則の違反が見つかった場合は、図を書き込んだうえで architecture.ViolationError を返す。
+//
+// flagValues.Ignore は参照せず、 ignoredDirectories を除外するディレクトリとする。
+func Generate(flagValues FlagValues, dirs []string, ignoredDirectories []string) error {
+	var e emitter.Emitter
+	var language output.Language
+	switch flagValues.Format {
+	case FormatPlantUML:
+		e = emitter.NewPlantUMLPackageEmitter()
+		language = output.LanguagePlantUML
+	case FormatDOT:
+		e = emitter.NewDOTPackageEmitter()
+		language = output.LanguageDOT
+	default:
+		return fmt.Errorf("unsupported format %s", flagValues.Format)
 	}
-	var dirs []string
-	for _, dir := range args {
-		fi, err := os.Stat(dir)
-		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("could not find directory %s", dir)
+	if flagValues.Render != "" {
+		if err := output.ValidateImageFormat(flagValues.Render, flagValues.Check); err != nil {
+			return err
 		}
-		if !fi.Mode().IsDir() {
-			return nil, fmt.Errorf("%s is not a directory", dir)
-		}
-		dirAbs, err := filepath.Abs(dir)
-		if err != nil {
-			return nil, fmt.Errorf("could not find directory %s", dir)
+	}
+	rules, err := architecture.ParseRules(flagValues.ForbidImports)
+	if err != nil {
+		return err
+	}
+	var colorBy metrics.Metric
+	if flagValues.ColorBy != "" {
+		if colorBy, err = metrics.ParseMetric(flagValues.ColorBy); err != nil {
+			return err
 		}
-		dirs = append(dirs, dirAbs)
 	}
-	return dirs, nil
-}
 
-func getIgnoredDirectories(list string) ([]string, error) {
-	var result []string
-	list = strings.TrimSpace(list)
-	if list == "" {
-		return result, nil
+	cd, err := pkg.NewDiagramWithOption(dirs, ignoredDirectories, pkg.Options{
+		Theme:                  flagValues.Theme,
+		Recursive:              flagValues.Recursive,
+		Depth:                  flagValues.Depth,
+		RenderExternalPackages: flagValues.RenderExternalPackages,
+		HideStdlib:             flagValues.HideStdlib,
+		HideThirdParty:         flagValues.HideThirdParty,
+		CollapseThirdParty:     flagValues.CollapseThirdParty,
+		ColorBy:                colorBy,
+	})
+	if err != nil {
+		return err
 	}
-	split := strings.Split(list, ",")
-	for _, dir := range split {
-		dirAbs, err := filepath.Abs(strings.TrimSpace(dir))
-		if err != nil {
-			return nil, fmt.Errorf("could not find directory %s", dir)
-		}
-		result = append(result, dirAbs)
+
+	m := cd.Model()
+	// 図に出力しない外部パッケージへの import も検証するため、表示のオプションによらない依存関係で検証する。
+	report := architecture.Analyze(cd.DependencyModel(), rules, flagValues.DetectCycles)
+	report.Mark(m, cd.DisplayPath)
+	rendered := e.Emit(m)
+	switch {
+	case flagValues.Render != "":
+		err = output.WriteImage(flagValues.Output, language, rendered, output.ImageOptions{
+			Format:      flagValues.Render,
+			PlantUMLJar: flagValues.PlantUMLJar,
+		})
+	case flagValues.Check:
+		err = output.Check(flagValues.Output, rendered)
+	default:
+		err = output.Write(flagValues.Output, rendered)
+	}
+	if err != nil {
+		return err
+	}
+	if report.HasViolations() {
+		return &architecture.ViolationError{Report: report}
 	}
-	return result, nil
+	return nil
 }
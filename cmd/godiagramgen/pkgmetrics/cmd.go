@@ -0,0 +1,124 @@
+package pkgmetrics
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/keisuke-m123/godiagramgen/cmd/godiagramgen/output"
+	"github.com/keisuke-m123/godiagramgen/diagram/directory"
+	"github.com/keisuke-m123/godiagramgen/diagram/metrics"
+	"github.com/keisuke-m123/godiagramgen/diagram/pkg"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	FlagIgnore    = "ignore"
+	FlagOutput    = "output"
+	FlagRecursive = "recursive"
+	FlagFormat    = "format"
+	FlagCheck     = "check"
+	FlagDepth     = "depth"
+)
+
+const (
+	FormatTable = "table"
+	FormatCSV   = "csv"
+	FormatJSON  = "json"
+)
+
+type FlagValues struct {
+	Ignore    string
+	Output    string
+	Recursive bool
+	Format    string
+	Check     bool
+	Depth     int
+}
+
+type FlagSet struct {
+	set    *pflag.FlagSet
+	values FlagValues
+}
+
+func (fs *FlagSet) InitializeFlags() {
+	s := fs.set
+	vs := &fs.values
+	s.StringVar(&vs.Ignore, FlagIgnore, "", "Comma separated list of folders to ignore")
+	s.StringVar(&vs.Output, FlagOutput, "", "Output file path. If omitted, then this will default to standard output")
+	s.BoolVar(&vs.Recursive, FlagRecursive, true, "Walk all directories recursively (use --recursive=false to report only the given directories)")
+	s.StringVar(&vs.Format, FlagFormat, FormatTable, "Output format (table, csv, json)")
+	s.IntVar(&vs.Depth, FlagDepth, 0, "Collapse packages deeper than this number of path segments below the common path of all packages into their ancestor (0 reports all packages)")
+	s.BoolVar(&vs.Check, FlagCheck, false, "Compare the generated report with the output file instead of writing it, and fail with a unified diff if they differ")
+}
+
+func (fs *FlagSet) Values() FlagValues {
+	return fs.values
+}
+
+func NewMetricsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "metrics",
+		Short: "report coupling metrics (afferent, efferent, instability, abstractness, distance) of specified packages",
+	}
+
+	fs := FlagSet{set: cmd.PersistentFlags()}
+	fs.InitializeFlags()
+
+	cmd.Run = func(cmd *cobra.Command, args []string) { run(fs.Values(), args) }
+
+	return cmd
+}
+
+func run(flagValues FlagValues, args []string) {
+	dirs, err := directory.FromArgs(args)
+	if err != nil {
+		fmt.Println("usage:\ngodiagramgen metrics <DIR>\nDIR Must be a valid directory")
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	ignoredDirectories, err := directory.IgnoredFromList(flagValues.Ignore)
+	if err != nil {
+		fmt.Println("usage:\ngodiagramgen metrics [-ignore=<DIRLIST>]\nDIRLIST Must be a valid comma separated list of existing directories")
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	if err := Generate(flagValues, dirs, ignoredDirectories); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+// Generate は dirs のパッケージ毎のメトリクスを flagValues に従って計算し、 flagValues.Output (省略時は標準出力) に書き込む。
+// flagValues.Check の場合は書き込まずに flagValues.Output と比較し、異なる場合は output.StaleError を返す。
+//
+// 結合度は dirs のパッケージ間の import のみを数える。
+// flagValues.Ignore は参照せず、 ignoredDirectories を除外するディレクトリとする。
+func Generate(flagValues FlagValues, dirs []string, ignoredDirectories []string) error {
+	var render func([]*metrics.Package) string
+	switch flagValues.Format {
+	case FormatTable:
+		render = metrics.RenderTable
+	case FormatCSV:
+		render = metrics.RenderCSV
+	case FormatJSON:
+		render = metrics.RenderJSON
+	default:
+		return fmt.Errorf("unsupported format %s", flagValues.Format)
+	}
+
+	d, err := pkg.NewDiagramWithOption(dirs, ignoredDirectories, pkg.Options{
+		Recursive: flagValues.Recursive,
+		Depth:     flagValues.Depth,
+	})
+	if err != nil {
+		return err
+	}
+
+	rendered := render(d.Metrics())
+	if flagValues.Check {
+		return output.Check(flagValues.Output, rendered)
+	}
+	return output.Write(flagValues.Output, rendered)
+}
@@ -2,7 +2,13 @@ package main
 
 import (
 	"github.com/keisuke-m123/godiagramgen/cmd/godiagramgen/classdiagram"
+	"github.com/keisuke-m123/godiagramgen/cmd/godiagramgen/diffdiagram"
+	"github.com/keisuke-m123/godiagramgen/cmd/godiagramgen/erdiagram"
+	"github.com/keisuke-m123/godiagramgen/cmd/godiagramgen/generate"
+	"github.com/keisuke-m123/godiagramgen/cmd/godiagramgen/implementsreport"
 	"github.com/keisuke-m123/godiagramgen/cmd/godiagramgen/pkgdiagram"
+	"github.com/keisuke-m123/godiagramgen/cmd/godiagramgen/pkgmetrics"
+	"github.com/keisuke-m123/godiagramgen/cmd/godiagramgen/sequencediagram"
 	"github.com/spf13/cobra"
 )
 
@@ -14,6 +20,13 @@ func main() {
 	root.AddCommand(
 		classdiagram.NewClassDiagramGenCommand(),
 		pkgdiagram.NewPackageDiagramGenCommand(),
+		generate.NewGenerateCommand(),
+		generate.NewCheckCommand(),
+		diffdiagram.NewDiffCommand(),
+		pkgmetrics.NewMetricsCommand(),
+		sequencediagram.NewSequenceDiagramGenCommand(),
+		implementsreport.NewImplementsCommand(),
+		erdiagram.NewERDiagramGenCommand(),
 	)
 	if err := root.Execute(); err != nil {
 		panic(err)
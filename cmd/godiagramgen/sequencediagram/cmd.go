@@ -0,0 +1,137 @@
+package sequencediagram
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/keisuke-m123/godiagramgen/cmd/godiagramgen/output"
+	"github.com/keisuke-m123/godiagramgen/diagram/directory"
+	"github.com/keisuke-m123/godiagramgen/diagram/sequence"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	FlagIgnore          = "ignore"
+	FlagOutput          = "output"
+	FlagRecursive       = "recursive"
+	FlagCheck           = "check"
+	FlagEntry           = "entry"
+	FlagDepth           = "depth"
+	FlagIncludePackages = "include-packages"
+	FlagExcludePackages = "exclude-packages"
+	FlagRender          = "render"
+	FlagPlantUMLJar     = "plantuml-jar"
+)
+
+// DefaultDepth は起点から辿る呼び出しのネストの深さの既定値。
+const DefaultDepth = 3
+
+type FlagValues struct {
+	Ignore          string
+	Output          string
+	Recursive       bool
+	Check           bool
+	Entry           string
+	Depth           int
+	IncludePackages string
+	ExcludePackages string
+	Render          string
+	PlantUMLJar     string
+}
+
+type FlagSet struct {
+	set    *pflag.FlagSet
+	values FlagValues
+}
+
+func (fs *FlagSet) InitializeFlags() {
+	s := fs.set
+	vs := &fs.values
+	s.StringVar(&vs.Ignore, FlagIgnore, "", "Comma separated list of folders to ignore")
+	s.StringVar(&vs.Output, FlagOutput, "", "Output file path. If omitted, then this will default to standard output")
+	s.BoolVar(&vs.Recursive, FlagRecursive, false, "Walk all directories recursively")
+	s.StringVar(&vs.Entry, FlagEntry, "", "Entry function (pkg.Func or pkg.Type.Method) to start following calls from (required)")
+	s.IntVar(&vs.Depth, FlagDepth, DefaultDepth, "Number of nested calls to follow from the entry function (0 follows all calls)")
+	s.StringVar(&vs.IncludePackages, FlagIncludePackages, "", "Comma separated list of import path patterns (glob, or regexp prefixed with re:) of packages to follow calls into")
+	s.StringVar(&vs.ExcludePackages, FlagExcludePackages, "", "Comma separated list of import path patterns (glob, or regexp prefixed with re:) of packages not to follow calls into")
+	s.BoolVar(&vs.Check, FlagCheck, false, "Compare the generated diagram with the output file instead of writing it, and fail with a unified diff if they differ")
+	s.StringVar(&vs.Render, FlagRender, "", "Render the diagram as an image (svg, png) with a local PlantUML jar file (or plantuml command) instead of writing the source")
+	s.StringVar(&vs.PlantUMLJar, FlagPlantUMLJar, "", "PlantUML jar file path used by --render. If omitted, then this will default to $PLANTUML_JAR, or plantuml command in PATH")
+}
+
+func (fs *FlagSet) Values() FlagValues {
+	return fs.values
+}
+
+func NewSequenceDiagramGenCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sequence",
+		Short: "generate sequence diagram of calls statically followed from the entry function",
+	}
+
+	fs := FlagSet{set: cmd.PersistentFlags()}
+	fs.InitializeFlags()
+
+	cmd.Run = func(cmd *cobra.Command, args []string) { run(fs.Values(), args) }
+
+	return cmd
+}
+
+func run(flagValues FlagValues, args []string) {
+	dirs, err := directory.FromArgs(args)
+	if err != nil {
+		fmt.Println("usage:\ngodiagramgen sequence --entry=<pkg.Type.Method> <DIR>\nDIR Must be a valid directory")
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	ignoredDirectories, err := directory.IgnoredFromList(flagValues.Ignore)
+	if err != nil {
+		fmt.Println("usage:\ngodiagramgen sequence [-ignore=<DIRLIST>]\nDIRLIST Must be a valid comma separated list of existing directories")
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	if err := Generate(flagValues, dirs, ignoredDirectories); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+// Generate は dirs のパッケージから flagValues.Entry を起点とするシーケンス図を生成し、 flagValues.Output (省略時は標準出力) に書き込む。
+// flagValues.Check の場合は書き込まずに flagValues.Output と比較し、異なる場合は output.StaleError を返す。
+// flagValues.Render を指定した場合は、 PlantUML で画像に変換して書き込む。
+//
+// flagValues.Ignore は参照せず、 ignoredDirectories を除外するディレクトリとする。
+func Generate(flagValues FlagValues, dirs []string, ignoredDirectories []string) error {
+	if flagValues.Entry == "" {
+		return fmt.Errorf("--%s is required", FlagEntry)
+	}
+	if flagValues.Render != "" {
+		if err := output.ValidateImageFormat(flagValues.Render, flagValues.Check); err != nil {
+			return err
+		}
+	}
+	d, err := sequence.NewDiagramWithOption(dirs, ignoredDirectories, sequence.Options{
+		Entry:           flagValues.Entry,
+		Recursive:       flagValues.Recursive,
+		Depth:           flagValues.Depth,
+		IncludePackages: flagValues.IncludePackages,
+		ExcludePackages: flagValues.ExcludePackages,
+	})
+	if err != nil {
+		return err
+	}
+
+	rendered := d.Render()
+	if flagValues.Render != "" {
+		return output.WriteImage(flagValues.Output, output.LanguagePlantUML, rendered, output.ImageOptions{
+			Format:      flagValues.Render,
+			PlantUMLJar: flagValues.PlantUMLJar,
+		})
+	}
+	if flagValues.Check {
+		return output.Check(flagValues.Output, rendered)
+	}
+	return output.Write(flagValues.Output, rendered)
+}
@@ -0,0 +1,242 @@
+// Package architecture はパッケージ図のモデルから import の循環とレイヤー規則の違反を検出する。
+//
+// Go はパッケージ間の import の循環を許さないため、循環は --depth でまとめたパッケージの間にのみ現れる。
+// gocode はテストファイルを読み込まないため、テストのみで使う import は検出の対象とならない。
+//
+// 図に出力しない外部パッケージへの import も検証するため、 pkg.Diagram.DependencyModel を検証し、
+// Report.Mark で図のモデルに違反を反映する。
+package architecture
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/keisuke-m123/godiagramgen/diagram/filter"
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+)
+
+// RuleSeparator は規則の import してはならないパッケージの前に置く区切り。
+const RuleSeparator = "->"
+
+type (
+	// Rule は from に一致するパッケージが to に一致するパッケージを import してはならないという規則を表す。
+	Rule struct {
+		source string
+		from   filter.Patterns
+		to     filter.Patterns
+	}
+
+	// Violation は循環に含まれる、または規則に違反する import を表す。
+	Violation struct {
+		From string
+		To   string
+		// Rule は違反した規則を表す。循環に含まれる import の場合は nil とする。
+		Rule *Rule
+	}
+
+	// Report は検出した循環と違反を表す。
+	Report struct {
+		// Cycles は互いに import で到達できるパッケージの集合(強連結成分)をパスの順で表す。
+		Cycles     [][]string
+		Violations []*Violation
+	}
+
+	// ViolationError は循環または規則の違反が見つかったことを表す。
+	ViolationError struct {
+		Report *Report
+	}
+)
+
+// ParseRules はカンマ区切りの規則の一覧を解釈する。区切り方は filter.SplitPatterns と同一とする。
+//
+// 規則は "FROM->TO" の形式とし、 FROM と TO はパッケージのインポートパスに一致させるパターン(filter.Pattern)とする。
+func ParseRules(list string) ([]*Rule, error) {
+	var rules []*Rule
+	for _, trimmed := range filter.SplitPatterns(list) {
+		from, to, ok := cut(trimmed, RuleSeparator)
+		if !ok {
+			return nil, fmt.Errorf("invalid rule %s: want FROM%sTO", trimmed, RuleSeparator)
+		}
+		fromPatterns, err := filter.ParsePatterns(from)
+		if err != nil {
+			return nil, err
+		}
+		toPatterns, err := filter.ParsePatterns(to)
+		if err != nil {
+			return nil, err
+		}
+		if len(fromPatterns) == 0 || len(toPatterns) == 0 {
+			return nil, fmt.Errorf("invalid rule %s: want FROM%sTO", trimmed, RuleSeparator)
+		}
+		rules = append(rules, &Rule{source: trimmed, from: fromPatterns, to: toPatterns})
+	}
+	return rules, nil
+}
+
+// cut は strings.Cut と同様に s を sep の前後に分割する。
+func cut(s, sep string) (before, after string, found bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+func (r *Rule) String() string {
+	return r.source
+}
+
+func (r *Rule) forbids(from, to string) bool {
+	return r.from.MatchAny(from) && r.to.MatchAny(to)
+}
+
+// Analyze は d の import の循環と rules の違反を検出する。 detectCycles が false の場合は循環を検出しない。
+//
+// 循環に含まれる、または規則に違反する関連には model.Edge の Violation を設定する。
+func Analyze(d *model.Diagram, rules []*Rule, detectCycles bool) *Report {
+	report := &Report{}
+	components := make(map[string]int)
+	if detectCycles {
+		for i, cycle := range stronglyConnectedComponents(d) {
+			report.Cycles = append(report.Cycles, cycle)
+			for _, p := range cycle {
+				components[p] = i
+			}
+		}
+	}
+
+	for _, edge := range d.Edges() {
+		from, to := edge.From.Package.Path, edge.To.Package.Path
+		ci, inFrom := components[from]
+		cj, inTo := components[to]
+		if inFrom && inTo && ci == cj {
+			edge.Violation = true
+			report.Violations = append(report.Violations, &Violation{From: from, To: to})
+		}
+		for _, rule := range rules {
+			if rule.forbids(from, to) {
+				edge.Violation = true
+				report.Violations = append(report.Violations, &Violation{From: from, To: to, Rule: rule})
+			}
+		}
+	}
+	return report
+}
+
+// Mark は d のうち、 r の循環に含まれる、または規則に違反する import の関連に model.Edge の Violation を設定する。
+//
+// displayPath は Analyze したモデルのパッケージのパスを d のパッケージのパスに変換する。
+// d に出力されない import は設定しない。
+func (r *Report) Mark(d *model.Diagram, displayPath func(string) string) {
+	type key struct{ from, to string }
+	violated := make(map[key]struct{})
+	for _, v := range r.Violations {
+		violated[key{from: displayPath(v.From), to: displayPath(v.To)}] = struct{}{}
+	}
+	for _, edge := range d.Edges() {
+		if _, ok := violated[key{from: edge.From.Package.Path, to: edge.To.Package.Path}]; ok {
+			edge.Violation = true
+		}
+	}
+}
+
+func (r *Report) HasViolations() bool {
+	return len(r.Cycles) > 0 || len(r.Violations) > 0
+}
+
+// String は循環と違反を一行ずつ表す。
+func (r *Report) String() string {
+	var lines []string
+	for _, cycle := range r.Cycles {
+		lines = append(lines, fmt.Sprintf("import cycle: %s", strings.Join(cycle, ", ")))
+	}
+	for _, v := range r.Violations {
+		if v.Rule == nil {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("forbidden import: %s imports %s (rule %s)", v.From, v.To, v.Rule))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e *ViolationError) Error() string {
+	return e.Report.String()
+}
+
+// stronglyConnectedComponents は Tarjan のアルゴリズムで二つ以上のパッケージからなる強連結成分を求める。
+// 成分はパスの順に並べ、成分同士は最初のパスの順に並べる。
+func stronglyConnectedComponents(d *model.Diagram) [][]string {
+	graph := make(map[string][]string)
+	var paths []string
+	for _, p := range d.Packages {
+		paths = append(paths, p.Path)
+		for _, edge := range p.Edges {
+			graph[p.Path] = append(graph[p.Path], edge.To.Package.Path)
+		}
+	}
+	sort.Strings(paths)
+
+	t := &tarjan{graph: graph, index: make(map[string]int), lowLink: make(map[string]int), onStack: make(map[string]bool)}
+	for _, p := range paths {
+		if _, ok := t.index[p]; !ok {
+			t.visit(p)
+		}
+	}
+
+	sort.Slice(t.components, func(i, j int) bool {
+		return strings.Compare(t.components[i][0], t.components[j][0]) < 0
+	})
+	return t.components
+}
+
+type tarjan struct {
+	graph      map[string][]string
+	next       int
+	index      map[string]int
+	lowLink    map[string]int
+	stack      []string
+	onStack    map[string]bool
+	components [][]string
+}
+
+func (t *tarjan) visit(p string) {
+	t.index[p] = t.next
+	t.lowLink[p] = t.next
+	t.next++
+	t.stack = append(t.stack, p)
+	t.onStack[p] = true
+
+	for _, to := range t.graph[p] {
+		if _, ok := t.index[to]; !ok {
+			t.visit(to)
+			t.lowLink[p] = min(t.lowLink[p], t.lowLink[to])
+		} else if t.onStack[to] {
+			t.lowLink[p] = min(t.lowLink[p], t.index[to])
+		}
+	}
+
+	if t.lowLink[p] != t.index[p] {
+		return
+	}
+	var component []string
+	for {
+		top := t.stack[len(t.stack)-1]
+		t.stack = t.stack[:len(t.stack)-1]
+		t.onStack[top] = false
+		component = append(component, top)
+		if top == p {
+			break
+		}
+	}
+	if len(component) > 1 {
+		sort.Strings(component)
+		t.components = append(t.components, component)
+	}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,174 @@
+package architecture
+
+import (
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/keisuke-m123/godiagramgen/diagram/emitter"
+	"github.com/keisuke-m123/godiagramgen/diagram/pkg"
+	"github.com/keisuke-m123/godiagramgen/testutil"
+)
+
+const layersPackagePath = "github.com/keisuke-m123/godiagramgen/testingsupport/layers"
+
+func TestAnalyze(t *testing.T) {
+	d, err := pkg.NewDiagramWithOption(
+		[]string{path.Join(projectRootPath(), "testingsupport/layers")},
+		nil,
+		pkg.Options{Recursive: true, Depth: 1},
+	)
+	if err != nil {
+		t.Fatalf("failed newDiagram: %s", err)
+	}
+	rules, err := ParseRules("**/domain/**->**/infra/**, **/infra/**->**/app/**")
+	if err != nil {
+		t.Fatalf("failed ParseRules: %s", err)
+	}
+
+	m := d.Model()
+	report := Analyze(m, rules, true)
+
+	wantCycles := [][]string{{layersPackagePath + "/domain", layersPackagePath + "/infra"}}
+	if !cmp.Equal(wantCycles, report.Cycles) {
+		t.Errorf("failed cycles: %s", testutil.Diff(t, wantCycles, report.Cycles))
+	}
+	var forbidden []string
+	for _, v := range report.Violations {
+		if v.Rule != nil {
+			forbidden = append(forbidden, v.From+" "+v.To+" "+v.Rule.String())
+		}
+	}
+	wantForbidden := []string{layersPackagePath + "/domain " + layersPackagePath + "/infra **/domain/**->**/infra/**"}
+	if !cmp.Equal(wantForbidden, forbidden) {
+		t.Errorf("failed violations: %s", testutil.Diff(t, wantForbidden, forbidden))
+	}
+
+	wantFilePath := "../../testingsupport/layers-package.dot"
+	fileBytes, err := ioutil.ReadFile(wantFilePath)
+	if err != nil {
+		t.Fatalf("failed open want file %s: %s", wantFilePath, err)
+	}
+
+	got := emitter.NewDOTPackageEmitter().Emit(m)
+	want := string(fileBytes)
+
+	if got != want {
+		t.Errorf(
+			"failed render: want %s\n\ngot %s\n\ndiff: %s",
+			want,
+			got,
+			testutil.Diff(t, want, got),
+		)
+	}
+}
+
+func TestAnalyze_WithoutViolations(t *testing.T) {
+	d, err := pkg.NewDiagramWithOption(
+		[]string{path.Join(projectRootPath(), "testingsupport/layers")},
+		nil,
+		pkg.Options{Recursive: true},
+	)
+	if err != nil {
+		t.Fatalf("failed newDiagram: %s", err)
+	}
+	rules, err := ParseRules("**/infra/**->**/domain/service")
+	if err != nil {
+		t.Fatalf("failed ParseRules: %s", err)
+	}
+
+	if report := Analyze(d.Model(), rules, true); report.HasViolations() {
+		t.Errorf("want no violations: %s", report)
+	}
+}
+
+func TestAnalyze_ExternalPackages(t *testing.T) {
+	importsPackagePath := "github.com/keisuke-m123/godiagramgen/testingsupport/imports"
+	tests := []struct {
+		name    string
+		options pkg.Options
+		// wantMarked は図のモデルで違反として描画される import を表す。
+		wantMarked []string
+	}{
+		{
+			name:    "WithoutExternalPackages",
+			options: pkg.Options{Recursive: true},
+		},
+		{
+			name:       "WithExternalPackages",
+			options:    pkg.Options{Recursive: true, RenderExternalPackages: true},
+			wantMarked: []string{importsPackagePath + " fmt", importsPackagePath + "/store sync"},
+		},
+		{
+			name:    "HideStdlib",
+			options: pkg.Options{Recursive: true, RenderExternalPackages: true, HideStdlib: true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d, err := pkg.NewDiagramWithOption(
+				[]string{path.Join(projectRootPath(), "testingsupport/imports")},
+				nil,
+				test.options,
+			)
+			if err != nil {
+				t.Fatalf("failed newDiagram: %s", err)
+			}
+			rules, err := ParseRules("**->fmt, **->sync")
+			if err != nil {
+				t.Fatalf("failed ParseRules: %s", err)
+			}
+
+			report := Analyze(d.DependencyModel(), rules, false)
+			var forbidden []string
+			for _, v := range report.Violations {
+				forbidden = append(forbidden, v.From+" "+v.To)
+			}
+			// 図に出力しない標準ライブラリへの import も、表示のオプションによらず違反とする。
+			wantForbidden := []string{importsPackagePath + " fmt", importsPackagePath + "/store sync"}
+			if !cmp.Equal(wantForbidden, forbidden) {
+				t.Errorf("failed violations: %s", testutil.Diff(t, wantForbidden, forbidden))
+			}
+
+			m := d.Model()
+			report.Mark(m, d.DisplayPath)
+			var marked []string
+			for _, edge := range m.Edges() {
+				if edge.Violation {
+					marked = append(marked, edge.From.Package.Path+" "+edge.To.Package.Path)
+				}
+			}
+			if !cmp.Equal(test.wantMarked, marked) {
+				t.Errorf("failed marked edges: %s", testutil.Diff(t, test.wantMarked, marked))
+			}
+		})
+	}
+}
+
+func TestParseRules_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules string
+	}{
+		{name: "WithoutSeparator", rules: "**/domain/**"},
+		{name: "WithoutTo", rules: "**/domain/**->"},
+		{name: "InvalidRegexp", rules: "re:(->**/infra/**"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ParseRules(test.rules); err == nil {
+				t.Errorf("want error for rules %s", test.rules)
+			}
+		})
+	}
+}
+
+func projectRootPath() string {
+	_, b, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(b), "../../")
+}
@@ -0,0 +1,376 @@
+// Package declaration は gocode が解析の対象としないパッケージレベルの宣言(関数、定数、変数)や、
+// 型パラメータ、ドキュメントコメントなどの gocode が保持しない型の情報を参照するために、インポートパス毎に go/types のパッケージ情報を保持する。
+//
+// go/types のパッケージ情報は gocode が保持する型から辿る(FromRelations)。
+// ドキュメントコメントを参照する場合は、 gocode.LoadRelations と同じディレクトリを読み込み直して構文木も保持する(Load)。
+package declaration
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/keisuke-m123/goanalyzer/gocode"
+	"github.com/keisuke-m123/godiagramgen/diagram/directory"
+	"golang.org/x/tools/go/packages"
+)
+
+type (
+	// Declarations はインポートパス毎のパッケージレベルの宣言を保持する。
+	Declarations struct {
+		packages map[gocode.PackagePath]*types.Package
+		// docs はインポートパス毎に、型名または 型名.メンバー名 をキーとしてドキュメントコメントを保持する。
+		docs map[gocode.PackagePath]map[string]string
+	}
+)
+
+// newDeclarations は宣言を一つも保持しない Declarations を生成する。
+func newDeclarations() *Declarations {
+	return &Declarations{
+		packages: make(map[gocode.PackagePath]*types.Package),
+		docs:     make(map[gocode.PackagePath]map[string]string),
+	}
+}
+
+// FromRelations は relations が保持する型から、読み込んだパッケージの go/types のパッケージ情報を集める。
+//
+// パッケージを読み込み直さないため、ドキュメントコメントは保持しない。
+// 宣言された型から go/types のパッケージ情報を辿れないパッケージは保持しないため、 Resolved で判定する。
+func FromRelations(relations *gocode.Relations) *Declarations {
+	d := newDeclarations()
+	for _, pkg := range relations.Packages().AsSlice() {
+		pkgPath := pkg.Summary().Path()
+		for _, typ := range declaredTypes(pkg.Detail()) {
+			if p := findPackage(pkgPath.String(), typ, make(map[types.Type]struct{})); p != nil {
+				d.packages[pkgPath] = p
+				break
+			}
+		}
+	}
+	return d
+}
+
+// declaredTypes は pkgDetail で宣言された型と、 interface のメソッドと埋め込まれた要素の型を返す。
+func declaredTypes(pkgDetail *gocode.PackageDetail) []types.Type {
+	var typs []types.Type
+	for _, st := range pkgDetail.Structs() {
+		typs = append(typs, st.Type().GoType())
+	}
+	for _, dt := range pkgDetail.DefinedTypes() {
+		typs = append(typs, dt.Type().GoType())
+	}
+	for _, iface := range pkgDetail.Interfaces() {
+		for _, m := range iface.Methods() {
+			for _, p := range m.Parameters() {
+				typs = append(typs, p.Type().GoType())
+			}
+			for _, rv := range m.ReturnValues() {
+				typs = append(typs, rv.Type().GoType())
+			}
+		}
+		for _, e := range iface.Embeds() {
+			typs = append(typs, e.Type().GoType())
+		}
+	}
+	for _, alias := range pkgDetail.TypeAliases() {
+		typs = append(typs, alias.Type().GoType())
+	}
+	return typs
+}
+
+// findPackage は typ を構成する型のうち、 pkgPath のパッケージで宣言された型または型パラメータから go/types のパッケージ情報を返す。
+//
+// 他のパッケージの型はエクスポートデータから読み込まれたものであり、 unexported な宣言を含まないため辿らない。
+func findPackage(pkgPath string, typ types.Type, seen map[types.Type]struct{}) *types.Package {
+	if typ == nil {
+		return nil
+	}
+	if _, ok := seen[typ]; ok {
+		return nil
+	}
+	seen[typ] = struct{}{}
+
+	find := func(typs ...types.Type) *types.Package {
+		for _, t := range typs {
+			if p := findPackage(pkgPath, t, seen); p != nil {
+				return p
+			}
+		}
+		return nil
+	}
+	switch t := typ.(type) {
+	case *types.Named:
+		if pkg := t.Obj().Pkg(); pkg != nil && pkg.Path() == pkgPath {
+			return pkg
+		}
+		var args []types.Type
+		for i := 0; i < t.TypeArgs().Len(); i++ {
+			args = append(args, t.TypeArgs().At(i))
+		}
+		return find(args...)
+	case *types.TypeParam:
+		if pkg := t.Obj().Pkg(); pkg != nil && pkg.Path() == pkgPath {
+			return pkg
+		}
+		return nil
+	case *types.Pointer:
+		return find(t.Elem())
+	case *types.Slice:
+		return find(t.Elem())
+	case *types.Array:
+		return find(t.Elem())
+	case *types.Chan:
+		return find(t.Elem())
+	case *types.Map:
+		return find(t.Key(), t.Elem())
+	case *types.Signature:
+		return find(t.Params(), t.Results())
+	case *types.Tuple:
+		var elems []types.Type
+		for i := 0; i < t.Len(); i++ {
+			elems = append(elems, t.At(i).Type())
+		}
+		return find(elems...)
+	case *types.Struct:
+		var fields []types.Type
+		for i := 0; i < t.NumFields(); i++ {
+			fields = append(fields, t.Field(i).Type())
+		}
+		return find(fields...)
+	case *types.Interface:
+		var elems []types.Type
+		for i := 0; i < t.NumExplicitMethods(); i++ {
+			elems = append(elems, t.ExplicitMethod(i).Type())
+		}
+		for i := 0; i < t.NumEmbeddeds(); i++ {
+			elems = append(elems, t.EmbeddedType(i))
+		}
+		return find(elems...)
+	case *types.Union:
+		var terms []types.Type
+		for i := 0; i < t.Len(); i++ {
+			terms = append(terms, t.Term(i).Type())
+		}
+		return find(terms...)
+	default:
+		return nil
+	}
+}
+
+// Load は options に従ってディレクトリを読み込み、パッケージレベルの宣言を返す。
+//
+// ディレクトリは gocode.LoadRelations と同じ規則で列挙する(directory.Collect)。
+func Load(options *gocode.LoadOptions) (*Declarations, error) {
+	dirs, err := directory.Collect(options)
+	if err != nil {
+		return nil, err
+	}
+
+	d := newDeclarations()
+	for _, dir := range dirs {
+		if err := d.loadDirectory(dir); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+func (d *Declarations) loadDirectory(directoryPath string) error {
+	loadConfig := &packages.Config{
+		Mode: packages.NeedTypes |
+			packages.NeedTypesInfo |
+			packages.NeedSyntax |
+			packages.NeedName |
+			packages.NeedFiles |
+			packages.NeedImports,
+		Dir: directoryPath,
+	}
+	pkgs, err := packages.Load(loadConfig)
+	if err != nil {
+		return fmt.Errorf("load packages failed: %w", err)
+	}
+	for _, pkg := range pkgs {
+		if pkg.PkgPath == "." || pkg.Types == nil {
+			continue
+		}
+		d.packages[gocode.PackagePath(pkg.PkgPath)] = pkg.Types
+		d.docs[gocode.PackagePath(pkg.PkgPath)] = collectDocs(pkg.Syntax)
+	}
+	return nil
+}
+
+// Resolved は pkgPath のパッケージの go/types のパッケージ情報を保持しているかを返す。
+func (d *Declarations) Resolved(pkgPath gocode.PackagePath) bool {
+	_, ok := d.packages[pkgPath]
+	return ok
+}
+
+// Named は pkgPath のパッケージで宣言された name という名前の型を返す。
+func (d *Declarations) Named(pkgPath gocode.PackagePath, name string) (*types.Named, bool) {
+	pkg, ok := d.packages[pkgPath]
+	if !ok {
+		return nil, false
+	}
+	tn, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil, false
+	}
+	named, ok := tn.Type().(*types.Named)
+	return named, ok
+}
+
+// Doc は pkgPath のパッケージで宣言された型のドキュメントコメントを返す。
+// member を指定した場合は、その型のフィールドまたはメソッド(interface のメソッドを含む)のドキュメントコメントを返す。
+//
+// フィールドにドキュメントコメントが無い場合は行末のコメントを返す。
+func (d *Declarations) Doc(pkgPath gocode.PackagePath, typeName string, member string) string {
+	key := typeName
+	if member != "" {
+		key = typeName + "." + member
+	}
+	return d.docs[pkgPath][key]
+}
+
+// Functions は pkgPath のパッケージで宣言された関数を名前順で返す。
+func (d *Declarations) Functions(pkgPath gocode.PackagePath) []*types.Func {
+	var functions []*types.Func
+	for _, obj := range d.objects(pkgPath) {
+		if fn, ok := obj.(*types.Func); ok {
+			functions = append(functions, fn)
+		}
+	}
+	return functions
+}
+
+// Constants は pkgPath のパッケージで宣言された定数を宣言順で返す。
+//
+// iota を使った定数の並びを保つため、名前順ではなく宣言された位置の順とする。
+func (d *Declarations) Constants(pkgPath gocode.PackagePath) []*types.Const {
+	var constants []*types.Const
+	for _, obj := range d.objects(pkgPath) {
+		if c, ok := obj.(*types.Const); ok {
+			constants = append(constants, c)
+		}
+	}
+	sort.SliceStable(constants, func(i, j int) bool {
+		return constants[i].Pos() < constants[j].Pos()
+	})
+	return constants
+}
+
+// Variables は pkgPath のパッケージで宣言された変数を名前順で返す。
+func (d *Declarations) Variables(pkgPath gocode.PackagePath) []*types.Var {
+	var variables []*types.Var
+	for _, obj := range d.objects(pkgPath) {
+		if v, ok := obj.(*types.Var); ok {
+			variables = append(variables, v)
+		}
+	}
+	return variables
+}
+
+// objects は pkgPath のパッケージのスコープで宣言されたオブジェクトを名前順で返す。
+func (d *Declarations) objects(pkgPath gocode.PackagePath) []types.Object {
+	pkg, ok := d.packages[pkgPath]
+	if !ok {
+		return nil
+	}
+	scope := pkg.Scope()
+	names := scope.Names()
+	objects := make([]types.Object, 0, len(names))
+	for _, name := range names {
+		objects = append(objects, scope.Lookup(name))
+	}
+	return objects
+}
+
+// collectDocs は files で宣言された型と、そのフィールドとメソッドのドキュメントコメントを収集する。
+func collectDocs(files []*ast.File) map[string]string {
+	docs := make(map[string]string)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				if decl.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range decl.Specs {
+					ts, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					// go/doc と同様に、括弧でまとめていない宣言では type キーワードのコメントを型のコメントとする。
+					doc := ts.Doc
+					if doc == nil && !decl.Lparen.IsValid() {
+						doc = decl.Doc
+					}
+					setDoc(docs, ts.Name.Name, doc)
+					switch typ := ts.Type.(type) {
+					case *ast.StructType:
+						collectFieldDocs(docs, ts.Name.Name, typ.Fields)
+					case *ast.InterfaceType:
+						collectFieldDocs(docs, ts.Name.Name, typ.Methods)
+					}
+				}
+			case *ast.FuncDecl:
+				if decl.Recv == nil || len(decl.Recv.List) == 0 {
+					continue
+				}
+				if typeName, ok := baseTypeName(decl.Recv.List[0].Type); ok {
+					setDoc(docs, typeName+"."+decl.Name.Name, decl.Doc)
+				}
+			}
+		}
+	}
+	return docs
+}
+
+func collectFieldDocs(docs map[string]string, typeName string, fields *ast.FieldList) {
+	if fields == nil {
+		return
+	}
+	for _, field := range fields.List {
+		doc := field.Doc
+		if doc == nil {
+			doc = field.Comment
+		}
+		for _, name := range field.Names {
+			setDoc(docs, typeName+"."+name.Name, doc)
+		}
+		// 埋め込まれたフィールドは型名をフィールド名とする。
+		if len(field.Names) == 0 {
+			if name, ok := baseTypeName(field.Type); ok {
+				setDoc(docs, typeName+"."+name, doc)
+			}
+		}
+	}
+}
+
+func setDoc(docs map[string]string, key string, doc *ast.CommentGroup) {
+	if text := strings.TrimSpace(doc.Text()); text != "" {
+		docs[key] = text
+	}
+}
+
+// baseTypeName はポインタ、型引数、パッケージ名を除いた型名を返す。
+func baseTypeName(expr ast.Expr) (string, bool) {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name, true
+	case *ast.StarExpr:
+		return baseTypeName(expr.X)
+	case *ast.ParenExpr:
+		return baseTypeName(expr.X)
+	case *ast.IndexExpr:
+		return baseTypeName(expr.X)
+	case *ast.IndexListExpr:
+		return baseTypeName(expr.X)
+	case *ast.SelectorExpr:
+		return expr.Sel.Name, true
+	default:
+		return "", false
+	}
+}
@@ -4,7 +4,12 @@ import (
 	"fmt"
 
 	"github.com/keisuke-m123/goanalyzer/gocode"
+	"github.com/keisuke-m123/godiagramgen/diagram/class/declaration"
 	"github.com/keisuke-m123/godiagramgen/diagram/class/renderer"
+	"github.com/keisuke-m123/godiagramgen/diagram/emitter"
+	"github.com/keisuke-m123/godiagramgen/diagram/export"
+	"github.com/keisuke-m123/godiagramgen/diagram/implementation"
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
 	"github.com/keisuke-m123/godiagramgen/plantuml"
 	"github.com/spf13/afero"
 )
@@ -21,7 +26,41 @@ func newDiagramWithOptions(
 	if err != nil {
 		return nil, fmt.Errorf("failed to load relations: %w", err)
 	}
-	return &Diagram{renderer: renderer.NewRenderer(r, renderingOptions)}, nil
+	declarations := declaration.FromRelations(r)
+	if needsLoadingDeclarations(r, declarations, renderingOptions) {
+		declarations, err = declaration.Load(loadOptions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load declarations: %w", err)
+		}
+	}
+	rd := renderer.NewRenderer(r, declarations, renderingOptions)
+	if err := rd.ValidateFocus(); err != nil {
+		return nil, fmt.Errorf("invalid focus: %w", err)
+	}
+	return &Diagram{renderer: rd}, nil
+}
+
+// needsLoadingDeclarations は gocode が保持する型から辿った宣言では描画に足りず、パッケージを読み込み直す必要があるかを返す。
+//
+// ドキュメントコメントは構文木からのみ得られる。
+// 型から go/types のパッケージ情報を辿れないパッケージは、 interface を宣言している場合か、関数または変数を描画する場合のみ読み込み直す。
+func needsLoadingDeclarations(
+	relations *gocode.Relations,
+	declarations *declaration.Declarations,
+	renderingOptions *renderer.RenderingOptions,
+) bool {
+	if renderingOptions.RenderDocs {
+		return true
+	}
+	for _, pkg := range relations.Packages().AsSlice() {
+		if declarations.Resolved(pkg.Summary().Path()) {
+			continue
+		}
+		if len(pkg.Detail().Interfaces()) > 0 || renderingOptions.RenderFunctions || renderingOptions.RenderVariables {
+			return true
+		}
+	}
+	return false
 }
 
 func NewDiagram(
@@ -42,3 +81,42 @@ func NewDiagram(
 func (d *Diagram) Render() *plantuml.Result {
 	return d.renderer.Render()
 }
+
+// RenderPlantUML は解析結果を PlantUML 形式の文字列で返す。
+func (d *Diagram) RenderPlantUML() string {
+	return d.renderer.RenderPlantUML()
+}
+
+func (d *Diagram) RenderMermaid() string {
+	return d.renderer.RenderMermaid()
+}
+
+// RenderJSON は解析結果を export.Document の JSON 形式で返す。
+func (d *Diagram) RenderJSON() string {
+	return d.renderer.RenderWith(emitter.NewJSONEmitter())
+}
+
+// Export は解析結果を export.Document として返す。
+func (d *Diagram) Export() *export.Document {
+	return export.NewDocument(d.renderer.Build())
+}
+
+// Implementations は interface と、その interface を実装する型の対応を返す。
+func (d *Diagram) Implementations() []*implementation.Entry {
+	return d.renderer.Implementations()
+}
+
+// Schema は struct tag からテーブルとして扱う struct を抽出した、エンティティ関連図のモデルを返す。
+func (d *Diagram) Schema() *model.Schema {
+	return d.renderer.Schema()
+}
+
+// Model は出力形式に依存しない図のモデルを返す。
+func (d *Diagram) Model() *model.Diagram {
+	return d.renderer.Build()
+}
+
+// RenderWith は図のモデルを e で変換して返す。
+func (d *Diagram) RenderWith(e emitter.Emitter) string {
+	return d.renderer.RenderWith(e)
+}
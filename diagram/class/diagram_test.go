@@ -4,8 +4,15 @@ import (
 	"io/ioutil"
 	"testing"
 
+	"github.com/keisuke-m123/goanalyzer/gocode"
+	"github.com/keisuke-m123/godiagramgen/diagram/class/declaration"
 	"github.com/keisuke-m123/godiagramgen/diagram/class/renderer"
+	"github.com/keisuke-m123/godiagramgen/diagram/emitter"
+	"github.com/keisuke-m123/godiagramgen/diagram/filter"
+	"github.com/keisuke-m123/godiagramgen/diagram/implementation"
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
 	"github.com/keisuke-m123/godiagramgen/testutil"
+	"github.com/spf13/afero"
 )
 
 func TestClassDiagram_Render(t *testing.T) {
@@ -75,6 +82,89 @@ func TestClassDiagram_Render(t *testing.T) {
 			},
 			wantFilePath: "../../testingsupport/subfolder1-3.puml",
 		},
+		{
+			name:             "SameNamePackagesWithShortPackageNames",
+			renderingOptions: &renderer.RenderingOptions{ShortPackageNames: true},
+			recursive:        true,
+			directories:      []string{"../../testingsupport/samename"},
+			wantFilePath:     "../../testingsupport/samename.puml",
+		},
+		{
+			name:             "PackageFunctions",
+			renderingOptions: &renderer.RenderingOptions{RenderFunctions: true},
+			directories:      []string{"../../testingsupport/packagefunctions"},
+			wantFilePath:     "../../testingsupport/packagefunctions.puml",
+		},
+		{
+			name:             "ConstantsAndVariables",
+			renderingOptions: &renderer.RenderingOptions{RenderConstants: true, RenderVariables: true},
+			directories:      []string{"../../testingsupport/constants"},
+			wantFilePath:     "../../testingsupport/constants.puml",
+		},
+		{
+			name:             "Generics",
+			renderingOptions: &renderer.RenderingOptions{},
+			directories:      []string{"../../testingsupport/generics"},
+			wantFilePath:     "../../testingsupport/generics.puml",
+		},
+		{
+			name:             "MethodDependencies",
+			renderingOptions: &renderer.RenderingOptions{RenderMethodDependencies: true},
+			directories:      []string{"../../testingsupport/methoddependencies"},
+			wantFilePath:     "../../testingsupport/methoddependencies.puml",
+		},
+		{
+			name: "Focus",
+			renderingOptions: &renderer.RenderingOptions{
+				Focus:      "focus.OrderService",
+				FocusDepth: 2,
+			},
+			directories:  []string{"../../testingsupport/focus"},
+			wantFilePath: "../../testingsupport/focus.puml",
+		},
+		{
+			name: "Filter",
+			renderingOptions: &renderer.RenderingOptions{
+				Filter: mustNewFilter(t, filter.Options{
+					ExcludePackages: "**/mocks/**",
+					IncludeTypes:    "*Service,*Repository,Order",
+				}),
+			},
+			directories:  []string{"../../testingsupport/filters"},
+			recursive:    true,
+			wantFilePath: "../../testingsupport/filters.puml",
+		},
+		{
+			name:             "APISurface",
+			renderingOptions: &renderer.RenderingOptions{APISurface: true, RenderFunctions: true},
+			directories:      []string{"../../testingsupport/apisurface"},
+			wantFilePath:     "../../testingsupport/apisurface.puml",
+		},
+		{
+			name:             "ImplementsWithRenderExternalPackages",
+			renderingOptions: &renderer.RenderingOptions{RenderExternalPackages: true},
+			recursive:        true,
+			directories:      []string{"../../testingsupport/implements"},
+			wantFilePath:     "../../testingsupport/implements.puml",
+		},
+		{
+			name:             "Tags",
+			renderingOptions: &renderer.RenderingOptions{RenderTags: true},
+			directories:      []string{"../../testingsupport/tags"},
+			wantFilePath:     "../../testingsupport/tags.puml",
+		},
+		{
+			name:             "SelectedTagsAndDocs",
+			renderingOptions: &renderer.RenderingOptions{TagKeys: []string{"json", "db"}, RenderDocs: true},
+			directories:      []string{"../../testingsupport/tags"},
+			wantFilePath:     "../../testingsupport/tags-docs.puml",
+		},
+		{
+			name:             "Multiplicity",
+			renderingOptions: &renderer.RenderingOptions{},
+			directories:      []string{"../../testingsupport/multiplicity"},
+			wantFilePath:     "../../testingsupport/multiplicity.puml",
+		},
 	}
 
 	for _, test := range tests {
@@ -89,13 +179,124 @@ func TestClassDiagram_Render(t *testing.T) {
 				t.Fatalf("failed newDiagramWithOptions: %s", err)
 			}
 
-			renderResult := d.Render()
 			fileBytes, err := ioutil.ReadFile(test.wantFilePath)
 			if err != nil {
 				t.Fatalf("failed open want file %s: %s", test.wantFilePath, err)
 			}
 
-			got := renderResult.String()
+			got := d.Render().String()
+			want := string(fileBytes)
+
+			if got != want {
+				t.Errorf(
+					"failed render: want %s\n\ngot %s\n\ndiff: %s",
+					want,
+					got,
+					testutil.Diff(t, want, got),
+				)
+			}
+		})
+	}
+}
+
+func TestClassDiagram_RenderMermaid(t *testing.T) {
+	tests := []struct {
+		name             string
+		renderingOptions *renderer.RenderingOptions
+		recursive        bool
+		directories      []string
+		wantFilePath     string
+	}{
+		{
+			name:             "TestingSupportAll",
+			renderingOptions: &renderer.RenderingOptions{},
+			recursive:        true,
+			directories:      []string{"../../testingsupport"},
+			wantFilePath:     "../../testingsupport/testingsupport-all.mmd",
+		},
+		{
+			name: "TestingSupport",
+			renderingOptions: &renderer.RenderingOptions{
+				Title: "Test Title",
+				Notes: "<b><u>Notes</u></b>\nExample 1\nExample 1 continues\nExample 2",
+			},
+			directories:  []string{"../../testingsupport"},
+			wantFilePath: "../../testingsupport/testingsupport.mmd",
+		},
+		{
+			name:             "PackageFunctions",
+			renderingOptions: &renderer.RenderingOptions{RenderFunctions: true},
+			directories:      []string{"../../testingsupport/packagefunctions"},
+			wantFilePath:     "../../testingsupport/packagefunctions.mmd",
+		},
+		{
+			name:             "ConstantsAndVariables",
+			renderingOptions: &renderer.RenderingOptions{RenderConstants: true, RenderVariables: true},
+			directories:      []string{"../../testingsupport/constants"},
+			wantFilePath:     "../../testingsupport/constants.mmd",
+		},
+		{
+			name:             "Generics",
+			renderingOptions: &renderer.RenderingOptions{},
+			directories:      []string{"../../testingsupport/generics"},
+			wantFilePath:     "../../testingsupport/generics.mmd",
+		},
+		{
+			name:             "MethodDependencies",
+			renderingOptions: &renderer.RenderingOptions{RenderMethodDependencies: true},
+			directories:      []string{"../../testingsupport/methoddependencies"},
+			wantFilePath:     "../../testingsupport/methoddependencies.mmd",
+		},
+		{
+			name: "FocusOutgoing",
+			renderingOptions: &renderer.RenderingOptions{
+				Focus:          "github.com/keisuke-m123/godiagramgen/testingsupport/focus.Order",
+				FocusDepth:     1,
+				FocusDirection: model.FocusDirectionOutgoing,
+			},
+			directories:  []string{"../../testingsupport/focus"},
+			wantFilePath: "../../testingsupport/focus-outgoing.mmd",
+		},
+		{
+			name: "FilterByKind",
+			renderingOptions: &renderer.RenderingOptions{
+				Filter: mustNewFilter(t, filter.Options{
+					IncludePackages: "re:/filters(/mocks)?$",
+					Kinds:           "struct,interface",
+					ExportedOnly:    true,
+				}),
+			},
+			directories:  []string{"../../testingsupport/filters"},
+			recursive:    true,
+			wantFilePath: "../../testingsupport/filters-kinds.mmd",
+		},
+		{
+			name:             "APISurface",
+			renderingOptions: &renderer.RenderingOptions{APISurface: true, RenderFunctions: true},
+			directories:      []string{"../../testingsupport/apisurface"},
+			wantFilePath:     "../../testingsupport/apisurface.mmd",
+		},
+		{
+			name:             "Multiplicity",
+			renderingOptions: &renderer.RenderingOptions{},
+			directories:      []string{"../../testingsupport/multiplicity"},
+			wantFilePath:     "../../testingsupport/multiplicity.mmd",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d, err := NewDiagram(test.directories, nil, test.recursive, test.renderingOptions)
+			if err != nil {
+				t.Fatalf("failed newDiagramWithOptions: %s", err)
+			}
+
+			fileBytes, err := ioutil.ReadFile(test.wantFilePath)
+			if err != nil {
+				t.Fatalf("failed open want file %s: %s", test.wantFilePath, err)
+			}
+
+			got := d.RenderMermaid()
 			want := string(fileBytes)
 
 			if got != want {
@@ -109,3 +310,177 @@ func TestClassDiagram_Render(t *testing.T) {
 		})
 	}
 }
+
+func TestClassDiagram_RenderJSON(t *testing.T) {
+	d, err := NewDiagram([]string{"../../testingsupport"}, nil, true, &renderer.RenderingOptions{})
+	if err != nil {
+		t.Fatalf("failed newDiagramWithOptions: %s", err)
+	}
+
+	wantFilePath := "../../testingsupport/testingsupport-all.json"
+	fileBytes, err := ioutil.ReadFile(wantFilePath)
+	if err != nil {
+		t.Fatalf("failed open want file %s: %s", wantFilePath, err)
+	}
+
+	got := d.RenderJSON()
+	want := string(fileBytes)
+
+	if got != want {
+		t.Errorf(
+			"failed render: want %s\n\ngot %s\n\ndiff: %s",
+			want,
+			got,
+			testutil.Diff(t, want, got),
+		)
+	}
+}
+
+func TestClassDiagram_Implementations(t *testing.T) {
+	d, err := NewDiagram(
+		[]string{"../../testingsupport/implements"},
+		nil,
+		true,
+		&renderer.RenderingOptions{},
+	)
+	if err != nil {
+		t.Fatalf("failed newDiagramWithOptions: %s", err)
+	}
+
+	wantFilePath := "../../testingsupport/implements.txt"
+	fileBytes, err := ioutil.ReadFile(wantFilePath)
+	if err != nil {
+		t.Fatalf("failed open want file %s: %s", wantFilePath, err)
+	}
+
+	got := implementation.RenderText(d.Implementations())
+	want := string(fileBytes)
+
+	if got != want {
+		t.Errorf(
+			"failed render: want %s\n\ngot %s\n\ndiff: %s",
+			want,
+			got,
+			testutil.Diff(t, want, got),
+		)
+	}
+}
+
+func TestClassDiagram_Schema(t *testing.T) {
+	tests := []struct {
+		name         string
+		emitter      func(*model.Schema) string
+		wantFilePath string
+	}{
+		{
+			name:         "PlantUML",
+			emitter:      emitter.NewPlantUMLERDEmitter().Emit,
+			wantFilePath: "../../testingsupport/erd.puml",
+		},
+		{
+			name:         "Mermaid",
+			emitter:      emitter.NewMermaidERDEmitter().Emit,
+			wantFilePath: "../../testingsupport/erd.mmd",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d, err := NewDiagram([]string{"../../testingsupport/erd"}, nil, false, &renderer.RenderingOptions{})
+			if err != nil {
+				t.Fatalf("failed newDiagramWithOptions: %s", err)
+			}
+
+			fileBytes, err := ioutil.ReadFile(test.wantFilePath)
+			if err != nil {
+				t.Fatalf("failed open want file %s: %s", test.wantFilePath, err)
+			}
+
+			got := test.emitter(d.Schema())
+			want := string(fileBytes)
+
+			if got != want {
+				t.Errorf(
+					"failed render: want %s\n\ngot %s\n\ndiff: %s",
+					want,
+					got,
+					testutil.Diff(t, want, got),
+				)
+			}
+		})
+	}
+}
+
+func TestClassDiagram_InvalidFocus(t *testing.T) {
+	tests := []struct {
+		name  string
+		focus string
+	}{
+		{name: "WithoutPackage", focus: "OrderService"},
+		{name: "NotFound", focus: "focus.NotFound"},
+		{name: "UnknownPackage", focus: "unknown.OrderService"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewDiagram(
+				[]string{"../../testingsupport/focus"},
+				nil,
+				false,
+				&renderer.RenderingOptions{Focus: test.focus, FocusDepth: 1},
+			)
+			if err == nil {
+				t.Errorf("want error for focus %s", test.focus)
+			}
+		})
+	}
+}
+
+func TestNeedsLoadingDeclarations(t *testing.T) {
+	tests := []struct {
+		name             string
+		directory        string
+		renderingOptions *renderer.RenderingOptions
+		want             bool
+	}{
+		{name: "Generics", directory: "../../testingsupport/generics", renderingOptions: &renderer.RenderingOptions{}, want: false},
+		{
+			name:             "Constants",
+			directory:        "../../testingsupport/constants",
+			renderingOptions: &renderer.RenderingOptions{RenderConstants: true},
+			want:             false,
+		},
+		{
+			name:             "Functions",
+			directory:        "../../testingsupport/packagefunctions",
+			renderingOptions: &renderer.RenderingOptions{RenderFunctions: true, RenderVariables: true},
+			want:             false,
+		},
+		{name: "Docs", directory: "../../testingsupport/tags", renderingOptions: &renderer.RenderingOptions{RenderDocs: true}, want: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			relations, err := gocode.LoadRelations(&gocode.LoadOptions{
+				Directories: []string{test.directory},
+				FileSystem:  afero.NewOsFs(),
+			})
+			if err != nil {
+				t.Fatalf("failed LoadRelations: %s", err)
+			}
+			declarations := declaration.FromRelations(relations)
+			if got := needsLoadingDeclarations(relations, declarations, test.renderingOptions); got != test.want {
+				t.Errorf("needsLoadingDeclarations() = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func mustNewFilter(t *testing.T, options filter.Options) *filter.Filter {
+	t.Helper()
+	f, err := filter.NewFilter(options)
+	if err != nil {
+		t.Fatalf("failed NewFilter: %s", err)
+	}
+	return f
+}
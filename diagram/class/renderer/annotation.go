@@ -0,0 +1,98 @@
+package renderer
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"github.com/keisuke-m123/goanalyzer/gocode"
+	"github.com/keisuke-m123/godiagramgen/diagram/class/declaration"
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+)
+
+type (
+	// annotationRenderer は型のノードに struct tag とドキュメントコメントを付与する。
+	annotationRenderer struct {
+		declarations *declaration.Declarations
+		renderTags   bool
+		// tagKeys は描画する struct tag のキーを表す。空の場合は全てのキーを描画する。
+		tagKeys    []string
+		renderDocs bool
+	}
+)
+
+func newAnnotationRenderer(
+	declarations *declaration.Declarations,
+	renderTags bool,
+	tagKeys []string,
+	renderDocs bool,
+) *annotationRenderer {
+	return &annotationRenderer{
+		declarations: declarations,
+		renderTags:   renderTags || len(tagKeys) > 0,
+		tagKeys:      tagKeys,
+		renderDocs:   renderDocs,
+	}
+}
+
+// annotate は pkg の型のノードに、描画が指定された struct tag とドキュメントコメントを設定する。
+func (r *annotationRenderer) annotate(pkg *model.Package) {
+	pkgPath := gocode.PackagePath(pkg.Path)
+	for _, node := range pkg.Nodes {
+		switch node.Kind {
+		case model.NodeKindStruct, model.NodeKindInterface, model.NodeKindConstraint,
+			model.NodeKindDefinedType, model.NodeKindTypeAlias:
+		default:
+			continue
+		}
+		if r.renderTags && node.Kind == model.NodeKindStruct {
+			r.annotateTags(pkgPath, node)
+		}
+		if r.renderDocs {
+			r.annotateDocs(pkgPath, node)
+		}
+	}
+}
+
+func (r *annotationRenderer) annotateTags(pkgPath gocode.PackagePath, node *model.Node) {
+	named, ok := r.declarations.Named(pkgPath, node.Name)
+	if !ok {
+		return
+	}
+	goStruct, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return
+	}
+	tags := make(map[string]string)
+	for i := 0; i < goStruct.NumFields(); i++ {
+		tags[goStruct.Field(i).Name()] = goStruct.Tag(i)
+	}
+	for _, f := range node.Fields {
+		f.Tag = r.selectTag(tags[f.Name])
+	}
+}
+
+// selectTag は tag から描画するキーのみを取り出し、 struct tag の形式で返す。
+func (r *annotationRenderer) selectTag(tag string) string {
+	if len(r.tagKeys) == 0 {
+		return tag
+	}
+	var selected []string
+	for _, key := range r.tagKeys {
+		if value, ok := reflect.StructTag(tag).Lookup(key); ok {
+			selected = append(selected, fmt.Sprintf("%s:%q", key, value))
+		}
+	}
+	return strings.Join(selected, " ")
+}
+
+func (r *annotationRenderer) annotateDocs(pkgPath gocode.PackagePath, node *model.Node) {
+	node.Doc = r.declarations.Doc(pkgPath, node.Name, "")
+	for _, f := range node.Fields {
+		f.Doc = r.declarations.Doc(pkgPath, node.Name, f.Name)
+	}
+	for _, m := range node.Methods {
+		m.Doc = r.declarations.Doc(pkgPath, node.Name, m.Name)
+	}
+}
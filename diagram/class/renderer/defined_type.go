@@ -1,107 +1,156 @@
 package renderer
 
 import (
-	"fmt"
+	"go/types"
 	"sort"
 	"strings"
 
 	"github.com/keisuke-m123/goanalyzer/gocode"
-	"github.com/keisuke-m123/godiagramgen/plantuml"
+	"github.com/keisuke-m123/godiagramgen/diagram/class/declaration"
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
 )
 
 type (
 	definedTypeRenderer struct {
-		relations      *gocode.Relations
-		methodRenderer *methodRenderer
+		relations    *gocode.Relations
+		declarations *declaration.Declarations
+		// implementationRenderer は defined type が実装する interface を判定する。
+		implementationRenderer *implementationRenderer
+		methodRenderer         *methodRenderer
+		renderConstants        bool
 	}
 )
 
-func newDefinedTypeRenderer(relations *gocode.Relations) *definedTypeRenderer {
+func newDefinedTypeRenderer(
+	relations *gocode.Relations,
+	declarations *declaration.Declarations,
+	implementationRenderer *implementationRenderer,
+	qualifier *packageQualifier,
+	renderConstants bool,
+) *definedTypeRenderer {
 	return &definedTypeRenderer{
-		relations:      relations,
-		methodRenderer: newMethodRenderer(),
+		relations:              relations,
+		declarations:           declarations,
+		implementationRenderer: implementationRenderer,
+		methodRenderer:         newMethodRenderer(qualifier),
+		renderConstants:        renderConstants,
 	}
 }
 
-func (r *definedTypeRenderer) buildRelations(pkgName gocode.PackageName) *plantuml.ElementStore {
-	elements := plantuml.NewElementStore()
-	for _, name := range r.sortedDefinedTypeNames(pkgName) {
-		dt, _ := r.relations.DefinedTypes().Get(pkgName, name)
-		elements.Add(r.buildRelation(pkgName, dt).AsSlice()...)
+func (r *definedTypeRenderer) buildRelations(pkgDetail *gocode.PackageDetail) []*model.Edge {
+	var edges []*model.Edge
+	for _, dt := range r.sortedDefinedTypes(pkgDetail) {
+		if edge, ok := r.buildRelation(dt); ok {
+			edges = append(edges, edge)
+		}
+		edges = append(edges, r.implementationRenderer.buildRelations(
+			newNodeRef(dt.PackageSummary(), dt.Name().String()),
+			dt.Type().GoType(),
+		)...)
 	}
-	return elements
+	return edges
 }
 
-func (r *definedTypeRenderer) buildRelation(pkgName gocode.PackageName, dt *gocode.DefinedType) *plantuml.ElementStore {
-	elements := plantuml.NewElementStore()
-
+func (r *definedTypeRenderer) buildRelation(dt *gocode.DefinedType) (*model.Edge, bool) {
 	typ := dt.UnderlyingType()
 	if typ.Builtin() {
-		return elements
+		return nil, false
 	}
 
-	typeName := typ.TypeName().String()
-	if renamed := generateRenamedName(typeName); typeName != renamed {
-		typeName = renamed
-		elements.Add(newTypedClass(pkgName.String(), typ.TypeName().String(), typeName))
+	return &model.Edge{
+		Kind: model.EdgeKindAlias,
+		From: newNodeRef(dt.PackageSummary(), dt.Name().String()),
+		To:   newNodeRef(typ.PackageSummary(), r.underlyingTypeName(typ)),
+	}, true
+}
+
+func (r *definedTypeRenderer) buildInPkg(pkgDetail *gocode.PackageDetail) []*model.Node {
+	var nodes []*model.Node
+	for _, definedType := range r.sortedDefinedTypes(pkgDetail) {
+		nodes = append(nodes, r.build(definedType))
+		if node, ok := r.buildUnderlyingType(definedType); ok {
+			nodes = append(nodes, node)
+		}
 	}
+	return nodes
+}
 
-	rtFrom := plantuml.NewRelationTargetWithNamespace(pkgName.String(), dt.Name().String())
-	rtTo := plantuml.NewRelationTargetWithNamespace(typ.PackageSummary().Name().String(), typeName)
-	elements.Add(plantuml.Relation(rtFrom, rtTo, plantuml.RelationTypeAlias))
+func (r *definedTypeRenderer) build(definedType *gocode.DefinedType) *model.Node {
+	var underlying string
+	if definedType.UnderlyingType().Builtin() {
+		underlying = definedType.UnderlyingType().TypeName().String()
+	}
 
-	return elements
+	return &model.Node{
+		Kind:       model.NodeKindDefinedType,
+		Package:    newPackageRef(definedType.PackageSummary()),
+		Name:       definedType.Name().String(),
+		Underlying: underlying,
+		TypeParams: r.buildTypeParams(definedType),
+		Constants:  r.buildConstants(definedType),
+		Methods:    r.methodRenderer.buildMethods(definedType.PackageSummary().Path(), definedType.Methods()),
+	}
 }
 
-func (r *definedTypeRenderer) buildInPkg(pkgName gocode.PackageName) *plantuml.ElementStore {
-	elements := plantuml.NewElementStore()
-	for _, name := range r.sortedDefinedTypeNames(pkgName) {
-		definedType, _ := r.relations.DefinedTypes().Get(pkgName, name)
-		elements.Add(r.build(definedType))
+func (r *definedTypeRenderer) buildTypeParams(definedType *gocode.DefinedType) []*model.TypeParam {
+	named, ok := definedType.Type().GoType().(*types.Named)
+	if !ok {
+		return nil
 	}
-	return elements
+	return buildTypeParams(named.TypeParams())
 }
 
-func (r *definedTypeRenderer) build(definedType *gocode.DefinedType) plantuml.Element {
-	color, _ := plantuml.ParseHexColor("#FF7700")
-	var stereotype string
-	if definedType.UnderlyingType().Builtin() {
-		stereotype = fmt.Sprintf("type of __%s__", definedType.UnderlyingType().TypeName().String())
+// buildConstants は definedType を型とする exported な定数を宣言順に返す。
+func (r *definedTypeRenderer) buildConstants(definedType *gocode.DefinedType) []*model.Constant {
+	if !r.renderConstants {
+		return nil
 	}
 
-	return plantuml.ClassWithOption(
-		definedType.Name().String(),
-		plantuml.ClassOptions{
-			Stereotype: plantuml.Stereotype(stereotype),
-			Spot: plantuml.Spot{
-				Name:  'D',
-				Color: color,
-			},
-		},
-		r.buildMethods(definedType).AsSlice()...,
-	)
+	pkgPath := definedType.PackageSummary().Path()
+	var constants []*model.Constant
+	for _, c := range r.declarations.Constants(pkgPath) {
+		if !c.Exported() {
+			continue
+		}
+		named, ok := c.Type().(*types.Named)
+		if !ok || named.Obj().Pkg() == nil || named.Obj().Name() != definedType.Name().String() {
+			continue
+		}
+		if gocode.PackagePath(named.Obj().Pkg().Path()) != pkgPath {
+			continue
+		}
+		constants = append(constants, &model.Constant{Name: c.Name(), Value: c.Val().String()})
+	}
+	return constants
 }
 
-func (r *definedTypeRenderer) buildMethods(definedType *gocode.DefinedType) *plantuml.ElementStore {
-	var orderedFunctions []*gocode.Function
-	methods := definedType.Methods()
-	for i := range methods {
-		orderedFunctions = append(orderedFunctions, methods[i])
+// buildUnderlyingType は builtin 以外の型を基底とする defined type について、基底の型を表すノードを返す。
+func (r *definedTypeRenderer) buildUnderlyingType(definedType *gocode.DefinedType) (*model.Node, bool) {
+	typ := definedType.UnderlyingType()
+	if typ.Builtin() {
+		return nil, false
 	}
-	sort.Slice(orderedFunctions, func(i, j int) bool {
-		return strings.Compare(orderedFunctions[i].Name().String(), orderedFunctions[j].Name().String()) < 0
-	})
-	return r.methodRenderer.buildMethods(orderedFunctions)
-}
 
-func (r *definedTypeRenderer) sortedDefinedTypeNames(pkgName gocode.PackageName) []gocode.DefinedTypeName {
-	definedTypes := r.relations.DefinedTypes().PackageDefinedTypes(pkgName)
-	var names []gocode.DefinedTypeName
-	for i := range definedTypes {
-		names = append(names, definedTypes[i].Name())
+	name := typeName(typ)
+	if renamed := r.underlyingTypeName(typ); renamed != name {
+		return &model.Node{
+			Kind:    model.NodeKindUnderlyingType,
+			Package: newPackageRef(definedType.PackageSummary()),
+			Name:    renamed,
+			Label:   name,
+		}, true
 	}
-	sort.Slice(names, func(i, j int) bool {
-		return strings.Compare(names[i].String(), names[j].String()) < 0
+	return nil, false
+}
+
+func (r *definedTypeRenderer) underlyingTypeName(typ *gocode.Type) string {
+	return generateRenamedName(typeName(typ))
+}
+
+func (r *definedTypeRenderer) sortedDefinedTypes(pkgDetail *gocode.PackageDetail) []*gocode.DefinedType {
+	definedTypes := append([]*gocode.DefinedType{}, pkgDetail.DefinedTypes()...)
+	sort.Slice(definedTypes, func(i, j int) bool {
+		return strings.Compare(definedTypes[i].Name().String(), definedTypes[j].Name().String()) < 0
 	})
-	return names
+	return definedTypes
 }
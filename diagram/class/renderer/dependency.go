@@ -0,0 +1,110 @@
+package renderer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/keisuke-m123/goanalyzer/gocode"
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+)
+
+type (
+	// dependencyRenderer はメソッドの引数と戻り値に現れる型への依存を描画する。
+	dependencyRenderer struct {
+		relations              *gocode.Relations
+		renderExternalPackages bool
+	}
+
+	// methodOwner はメソッドを持つ型(struct, interface, defined type)を表す。
+	methodOwner struct {
+		pkgSummary *gocode.PackageSummary
+		name       string
+		methods    []*gocode.Function
+	}
+)
+
+func newDependencyRenderer(relations *gocode.Relations, renderExternalPackages bool) *dependencyRenderer {
+	return &dependencyRenderer{
+		relations:              relations,
+		renderExternalPackages: renderExternalPackages,
+	}
+}
+
+func (r *dependencyRenderer) buildRelations(pkgDetail *gocode.PackageDetail) []*model.Edge {
+	var edges []*model.Edge
+	for _, owner := range r.sortedMethodOwners(pkgDetail) {
+		edges = append(edges, r.buildDependencies(owner)...)
+	}
+	return edges
+}
+
+// buildDependencies は owner のメソッドの引数と戻り値に現れる型への依存を、依存先毎に一つだけ返す。
+func (r *dependencyRenderer) buildDependencies(owner *methodOwner) []*model.Edge {
+	var types []*gocode.Type
+	for _, m := range owner.methods {
+		for _, p := range m.Parameters() {
+			types = append(types, p.Type().FundamentalTypes()...)
+		}
+		for _, rv := range m.ReturnValues() {
+			types = append(types, rv.Type().FundamentalTypes()...)
+		}
+	}
+	sort.SliceStable(types, func(i, j int) bool {
+		return strings.Compare(types[i].TypeName().String(), types[j].TypeName().String()) < 0
+	})
+
+	from := newNodeRef(owner.pkgSummary, owner.name)
+	uniqueRefSet := make(map[model.NodeRef]struct{})
+	var edges []*model.Edge
+	for _, typ := range types {
+		if typ.Builtin() || !r.isRenderingDependency(typ) {
+			continue
+		}
+		to := newNodeRef(typ.PackageSummary(), removePointerFromName(typ.TypeName().String()))
+		if _, ok := uniqueRefSet[to]; ok || to == from {
+			continue
+		}
+		uniqueRefSet[to] = struct{}{}
+
+		edges = append(edges, &model.Edge{
+			Kind: model.EdgeKindDependency,
+			From: from,
+			To:   to,
+		})
+	}
+	return edges
+}
+
+// isRenderingDependency は structRenderer.isRenderingAggregation と同様に、外部パッケージの型への依存を描画するかを判定する。
+func (r *dependencyRenderer) isRenderingDependency(typ *gocode.Type) bool {
+	return r.renderExternalPackages || r.relations.Packages().Contains(typ.PackageSummary().Path())
+}
+
+func (r *dependencyRenderer) sortedMethodOwners(pkgDetail *gocode.PackageDetail) []*methodOwner {
+	var owners []*methodOwner
+	for _, st := range pkgDetail.Structs() {
+		owners = append(owners, &methodOwner{
+			pkgSummary: st.PackageSummary(),
+			name:       st.Name().String(),
+			methods:    st.Methods(),
+		})
+	}
+	for _, iface := range pkgDetail.Interfaces() {
+		owners = append(owners, &methodOwner{
+			pkgSummary: iface.PackageSummary(),
+			name:       iface.Name().String(),
+			methods:    iface.Methods(),
+		})
+	}
+	for _, dt := range pkgDetail.DefinedTypes() {
+		owners = append(owners, &methodOwner{
+			pkgSummary: dt.PackageSummary(),
+			name:       dt.Name().String(),
+			methods:    dt.Methods(),
+		})
+	}
+	sort.Slice(owners, func(i, j int) bool {
+		return strings.Compare(owners[i].name, owners[j].name) < 0
+	})
+	return owners
+}
@@ -0,0 +1,399 @@
+package renderer
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/keisuke-m123/goanalyzer/gocode"
+	"github.com/keisuke-m123/godiagramgen/diagram/class/declaration"
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+)
+
+// columnTagKeys はテーブルの列を表す struct tag のキー。先に記述したキーの列名を優先する。
+var columnTagKeys = []string{"gorm", "db", "sql"}
+
+type (
+	// erdRenderer は db, gorm, sql の struct tag を持つ struct をテーブルとして、エンティティ関連図のモデルを生成する。
+	//
+	// テーブル名は struct の名前をスネークケースにしたものとする。
+	// フィールドは struct tag の列名(省略時はフィールド名をスネークケースにしたもの)の列とし、埋め込まれた struct のフィールドは展開する。
+	// 他のテーブルの struct を型とするタグの無いフィールドは、列ではなく関連として扱う。
+	erdRenderer struct {
+		declarations   *declaration.Declarations
+		structRenderer *structRenderer
+	}
+
+	// table はテーブルとして扱う struct と、その列を表す。
+	table struct {
+		entity  *model.Entity
+		named   *types.Named
+		columns []*tableColumn
+	}
+
+	tableColumn struct {
+		column *model.Column
+		// fieldName は列の元になったフィールドの名前を表す。
+		fieldName string
+		// references は外部キーとして参照するテーブルを表す。
+		references *table
+	}
+
+	// columnTag は列を表す struct tag を解析した結果を表す。
+	columnTag struct {
+		name    string
+		skip    bool
+		options map[string]string
+	}
+)
+
+func newERDRenderer(declarations *declaration.Declarations, structRenderer *structRenderer) *erdRenderer {
+	return &erdRenderer{
+		declarations:   declarations,
+		structRenderer: structRenderer,
+	}
+}
+
+// buildSchema は packages の struct からエンティティ関連図のモデルを生成する。
+//
+// 他のテーブルの struct に埋め込まれる struct (gorm.Model のような共通の列) はテーブルとしない。
+func (r *erdRenderer) buildSchema(packages []*gocode.Package) *model.Schema {
+	var candidates []*table
+	embeddedSet := make(map[string]struct{})
+	for _, pkg := range packages {
+		for _, st := range r.structRenderer.sortedStructs(pkg.Detail()) {
+			named, ok := r.declarations.Named(st.PackageSummary().Path(), st.Name().String())
+			if !ok || isGeneric(named) || !hasColumnTag(named) {
+				continue
+			}
+			candidates = append(candidates, &table{
+				entity: &model.Entity{
+					Table: snakeCase(st.Name().String()),
+					Type:  newNodeRef(st.PackageSummary(), st.Name().String()),
+				},
+				named: named,
+			})
+			for _, key := range embeddedStructKeys(named.Underlying().(*types.Struct)) {
+				embeddedSet[key] = struct{}{}
+			}
+		}
+	}
+
+	var tables []*table
+	tableByName := make(map[string]*table)
+	for _, t := range candidates {
+		if _, ok := embeddedSet[typeKey(t.named)]; ok {
+			continue
+		}
+		tables = append(tables, t)
+		tableByName[typeKey(t.named)] = t
+	}
+	assignEntityIDs(tables)
+
+	schema := &model.Schema{}
+	for _, t := range tables {
+		r.buildColumns(t, tables, tableByName)
+		schema.Entities = append(schema.Entities, t.entity)
+	}
+	for _, t := range tables {
+		for _, c := range t.columns {
+			if c.references == nil {
+				continue
+			}
+			schema.Relationships = append(schema.Relationships, &model.Relationship{
+				From:     t.entity,
+				To:       c.references.entity,
+				Column:   c.column.Name,
+				Nullable: c.column.Nullable,
+			})
+		}
+	}
+	return schema
+}
+
+// buildColumns は t の列を主キー、それ以外の列の順に、それぞれ宣言順で設定する。
+func (r *erdRenderer) buildColumns(t *table, tables []*table, tableByName map[string]*table) {
+	goStruct := t.named.Underlying().(*types.Struct)
+	// 関連のフィールドから推定する外部キーを、フィールド名をキーとして保持する。
+	associations := make(map[string]*table)
+	r.collectColumns(t, goStruct, tableByName, associations)
+
+	hasPrimaryKey := false
+	for _, c := range t.columns {
+		hasPrimaryKey = hasPrimaryKey || c.column.PrimaryKey
+	}
+	for _, c := range t.columns {
+		// 主キーを指定していない場合は id の列を主キーとする。
+		if !hasPrimaryKey && c.column.Name == "id" {
+			c.column.PrimaryKey = true
+		}
+		if referenced, ok := associations[c.fieldName]; ok {
+			c.references = referenced
+		} else if c.references == nil {
+			c.references = referencedTable(t, c.fieldName, tables)
+		}
+		c.column.ForeignKey = c.column.ForeignKey || c.references != nil
+	}
+
+	sort.SliceStable(t.columns, func(i, j int) bool {
+		return t.columns[i].column.PrimaryKey && !t.columns[j].column.PrimaryKey
+	})
+	for _, c := range t.columns {
+		t.entity.Columns = append(t.entity.Columns, c.column)
+	}
+}
+
+func (r *erdRenderer) collectColumns(
+	t *table,
+	goStruct *types.Struct,
+	tableByName map[string]*table,
+	associations map[string]*table,
+) {
+	for i := 0; i < goStruct.NumFields(); i++ {
+		f := goStruct.Field(i)
+		tag, tagged := parseColumnTag(reflect.StructTag(goStruct.Tag(i)))
+		if tag.skip {
+			continue
+		}
+		// 列名を指定していない埋め込まれた struct は、そのフィールドを列として展開する。
+		if f.Embedded() && tag.name == "" {
+			if embedded, ok := derefType(f.Type()).Underlying().(*types.Struct); ok {
+				r.collectColumns(t, embedded, tableByName, associations)
+				continue
+			}
+		}
+		if !f.Exported() {
+			continue
+		}
+		if !tagged || tag.name == "" {
+			if referenced, ok := associatedTable(f.Type(), tableByName); ok {
+				// 他のテーブルへの単一の関連は、 gorm と同様に フィールド名+ID (foreignKey の指定があればその名前) の列を外部キーとする。
+				if _, isSlice := f.Type().Underlying().(*types.Slice); !isSlice {
+					foreignKey := f.Name() + "ID"
+					if fk, ok := tag.options["foreignkey"]; ok && fk != "" {
+						foreignKey = fk
+					}
+					associations[foreignKey] = referenced
+				}
+				continue
+			}
+		}
+
+		name := tag.name
+		if name == "" {
+			name = snakeCase(f.Name())
+		}
+		t.columns = append(t.columns, &tableColumn{
+			column: &model.Column{
+				Name:       name,
+				Type:       columnTypeName(t.named.Obj().Pkg(), f.Type()),
+				PrimaryKey: tag.hasOption("primarykey", "primary_key", "pk"),
+				ForeignKey: tag.hasOption("fk", "foreignkey", "foreign_key"),
+				Nullable:   isNullable(f.Type()),
+			},
+			fieldName: f.Name(),
+		})
+	}
+}
+
+// embeddedStructKeys は goStruct に埋め込まれた struct (更に埋め込まれた struct を含む) の型を識別する名前を返す。
+func embeddedStructKeys(goStruct *types.Struct) []string {
+	var keys []string
+	for i := 0; i < goStruct.NumFields(); i++ {
+		f := goStruct.Field(i)
+		if !f.Embedded() {
+			continue
+		}
+		named, ok := derefType(f.Type()).(*types.Named)
+		if !ok {
+			continue
+		}
+		if embedded, ok := named.Underlying().(*types.Struct); ok {
+			keys = append(keys, typeKey(named))
+			keys = append(keys, embeddedStructKeys(embedded)...)
+		}
+	}
+	return keys
+}
+
+// hasColumnTag は named の struct (埋め込まれた struct を含む) のフィールドが列を表す struct tag を持つかを判定する。
+func hasColumnTag(named *types.Named) bool {
+	goStruct, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+	return structHasColumnTag(goStruct)
+}
+
+func structHasColumnTag(goStruct *types.Struct) bool {
+	for i := 0; i < goStruct.NumFields(); i++ {
+		if tag, tagged := parseColumnTag(reflect.StructTag(goStruct.Tag(i))); tagged && !tag.skip {
+			return true
+		}
+		f := goStruct.Field(i)
+		if !f.Embedded() {
+			continue
+		}
+		if embedded, ok := derefType(f.Type()).Underlying().(*types.Struct); ok && structHasColumnTag(embedded) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseColumnTag は gorm, db, sql の struct tag を解析する。 tagged はいずれかのキーを持つことを表す。
+//
+// gorm は column:name;primaryKey の形式、 db と sql は name,option の形式とする。オプション名は小文字に揃える。
+func parseColumnTag(structTag reflect.StructTag) (tag columnTag, tagged bool) {
+	tag.options = make(map[string]string)
+	for _, key := range columnTagKeys {
+		value, ok := structTag.Lookup(key)
+		if !ok {
+			continue
+		}
+		tagged = true
+		if value == "-" {
+			tag.skip = true
+			continue
+		}
+		var name string
+		if key == "gorm" {
+			for _, option := range strings.Split(value, ";") {
+				k, v := option, ""
+				if i := strings.Index(option, ":"); i >= 0 {
+					k, v = option[:i], option[i+1:]
+				}
+				k = strings.ToLower(strings.TrimSpace(k))
+				if k == "column" {
+					name = strings.TrimSpace(v)
+				} else if k != "" {
+					tag.options[k] = strings.TrimSpace(v)
+				}
+			}
+		} else {
+			elements := strings.Split(value, ",")
+			name = strings.TrimSpace(elements[0])
+			for _, option := range elements[1:] {
+				if option = strings.ToLower(strings.TrimSpace(option)); option != "" {
+					tag.options[option] = ""
+				}
+			}
+		}
+		if tag.name == "" {
+			tag.name = name
+		}
+	}
+	return tag, tagged
+}
+
+func (t columnTag) hasOption(names ...string) bool {
+	for _, name := range names {
+		if _, ok := t.options[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// associatedTable は typ (ポインタとスライスの要素を含む) がテーブルの struct である場合に、そのテーブルを返す。
+func associatedTable(typ types.Type, tableByName map[string]*table) (*table, bool) {
+	typ = derefType(typ)
+	if slice, ok := typ.Underlying().(*types.Slice); ok {
+		typ = derefType(slice.Elem())
+	}
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return nil, false
+	}
+	t, ok := tableByName[typeKey(named)]
+	return t, ok
+}
+
+// referencedTable は XxxID という名前のフィールドが参照するテーブル(Xxx という struct)を探す。
+// 同じパッケージのテーブルを優先し、他のパッケージで一つに定まらない場合は参照しないものとする。
+func referencedTable(from *table, fieldName string, tables []*table) *table {
+	if len(fieldName) <= len("ID") || !strings.HasSuffix(fieldName, "ID") {
+		return nil
+	}
+	name := strings.TrimSuffix(fieldName, "ID")
+	var candidates []*table
+	for _, t := range tables {
+		if t.named.Obj().Name() != name {
+			continue
+		}
+		if t.entity.Type.Package == from.entity.Type.Package {
+			return t
+		}
+		candidates = append(candidates, t)
+	}
+	if len(candidates) != 1 {
+		return nil
+	}
+	return candidates[0]
+}
+
+// assignEntityIDs はテーブル名をエンティティの ID とし、重複する場合はパッケージ名を前に付けて一意にする。
+func assignEntityIDs(tables []*table) {
+	counts := make(map[string]int)
+	for _, t := range tables {
+		counts[t.entity.Table]++
+	}
+	used := make(map[string]struct{})
+	for _, t := range tables {
+		id := t.entity.Table
+		if counts[id] > 1 {
+			id = t.entity.Type.Package.Name + "_" + id
+		}
+		base := id
+		for i := 2; ; i++ {
+			if _, ok := used[id]; !ok {
+				break
+			}
+			id = fmt.Sprintf("%s_%d", base, i)
+		}
+		used[id] = struct{}{}
+		t.entity.ID = id
+	}
+}
+
+// columnTypeName は pkg 以外のパッケージの型にパッケージ名を付けた型名を返す。
+func columnTypeName(pkg *types.Package, typ types.Type) string {
+	return types.TypeString(typ, func(p *types.Package) string {
+		if p.Path() == pkg.Path() {
+			return ""
+		}
+		return p.Name()
+	})
+}
+
+// isNullable は typ がポインタ、または database/sql の NullString などの NULL を表せる型であるかを判定する。
+func isNullable(typ types.Type) bool {
+	if _, ok := typ.(*types.Pointer); ok {
+		return true
+	}
+	named, ok := typ.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "database/sql" && strings.HasPrefix(named.Obj().Name(), "Null")
+}
+
+// snakeCase は Go の識別子をスネークケースに変換する。連続する大文字は一つの単語とする。(UserID は user_id、 HTTPServer は http_server)
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, c := range runes {
+		if unicode.IsUpper(c) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteRune('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(c))
+	}
+	return b.String()
+}
@@ -0,0 +1,66 @@
+package renderer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+)
+
+// ValidateFocus は RenderingOptions.Focus に指定された型が一意に定まるかを検証する。
+func (r *Renderer) ValidateFocus() error {
+	if r.renderingOptions.Focus == "" {
+		return nil
+	}
+	if r.renderingOptions.FocusDepth < 0 {
+		return fmt.Errorf("focus depth must not be negative: %d", r.renderingOptions.FocusDepth)
+	}
+	_, err := r.resolveFocus(r.buildFiltered())
+	return err
+}
+
+// resolveFocus は pkg.TypeName 形式で指定された型を d のノードから探す。
+//
+// pkg にはインポートパスとパッケージ名のどちらも指定できる。パッケージ名で複数の型に一致する場合はエラーとする。
+func (r *Renderer) resolveFocus(d *model.Diagram) (model.NodeRef, error) {
+	focus := r.renderingOptions.Focus
+	i := strings.LastIndex(focus, ".")
+	if i <= 0 || i == len(focus)-1 {
+		return model.NodeRef{}, fmt.Errorf("focus must be specified as pkg.TypeName: %s", focus)
+	}
+	pkg, name := focus[:i], focus[i+1:]
+
+	var candidates []model.NodeRef
+	for _, node := range d.Nodes() {
+		if !isFocusable(node) || node.Name != name {
+			continue
+		}
+		if node.Package.Path == pkg || node.Package.Name == pkg {
+			candidates = append(candidates, node.Ref())
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return model.NodeRef{}, fmt.Errorf("focus type not found: %s", focus)
+	case 1:
+		return candidates[0], nil
+	default:
+		paths := make([]string, 0, len(candidates))
+		for _, c := range candidates {
+			paths = append(paths, fmt.Sprintf("%s.%s", c.Package.Path, c.Name))
+		}
+		sort.Strings(paths)
+		return model.NodeRef{}, fmt.Errorf("focus type is ambiguous, specify one of: %s", strings.Join(paths, ", "))
+	}
+}
+
+// isFocusable は node が Go の型として宣言されたノードかを判定する。
+func isFocusable(node *model.Node) bool {
+	switch node.Kind {
+	case model.NodeKindStruct, model.NodeKindInterface, model.NodeKindConstraint, model.NodeKindDefinedType, model.NodeKindTypeAlias:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,122 @@
+package renderer
+
+import (
+	"go/token"
+	"go/types"
+	"strings"
+
+	"github.com/keisuke-m123/goanalyzer/gocode"
+	"github.com/keisuke-m123/godiagramgen/diagram/class/declaration"
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+)
+
+type (
+	// functionRenderer はパッケージレベルの関数を、パッケージ毎に一つのノードにまとめて描画する。
+	functionRenderer struct {
+		relations    *gocode.Relations
+		declarations *declaration.Declarations
+		qualifier    *packageQualifier
+	}
+)
+
+func newFunctionRenderer(
+	relations *gocode.Relations,
+	declarations *declaration.Declarations,
+	qualifier *packageQualifier,
+) *functionRenderer {
+	return &functionRenderer{
+		relations:    relations,
+		declarations: declarations,
+		qualifier:    qualifier,
+	}
+}
+
+func (r *functionRenderer) buildInPkg(pkgSummary *gocode.PackageSummary) []*model.Node {
+	functions := r.declarations.Functions(pkgSummary.Path())
+	if len(functions) == 0 {
+		return nil
+	}
+
+	node := &model.Node{
+		Kind:    model.NodeKindPackageFunctions,
+		Package: newPackageRef(pkgSummary),
+		Name:    model.PackageFunctionsNodeName,
+	}
+	for _, fn := range functions {
+		node.Methods = append(node.Methods, buildFunction(r.qualifier, pkgSummary.Path(), fn))
+	}
+	return []*model.Node{node}
+}
+
+// buildRelations はコンストラクタから生成する型への関連を返す。
+func (r *functionRenderer) buildRelations(pkgSummary *gocode.PackageSummary) []*model.Edge {
+	var edges []*model.Edge
+	for _, fn := range r.declarations.Functions(pkgSummary.Path()) {
+		named, ok := r.constructedType(fn)
+		if !ok {
+			continue
+		}
+		edges = append(edges, &model.Edge{
+			Kind: model.EdgeKindCreation,
+			From: newNodeRef(pkgSummary, model.PackageFunctionsNodeName),
+			To: model.NodeRef{
+				Package: model.PackageRef{
+					Path: named.Obj().Pkg().Path(),
+					Name: named.Obj().Pkg().Name(),
+				},
+				Name: named.Obj().Name(),
+			},
+			Label: fn.Name(),
+		})
+	}
+	return edges
+}
+
+// constructedType は fn が New から始まるコンストラクタであれば、最初の戻り値の型を返す。
+//
+// 戻り値の型は読み込んだパッケージで宣言されたものに限る。
+func (r *functionRenderer) constructedType(fn *types.Func) (*types.Named, bool) {
+	if !strings.HasPrefix(fn.Name(), "New") && !strings.HasPrefix(fn.Name(), "new") {
+		return nil, false
+	}
+	results := fn.Type().(*types.Signature).Results()
+	if results.Len() == 0 {
+		return nil, false
+	}
+
+	typ := results.At(0).Type()
+	if pointer, ok := typ.(*types.Pointer); ok {
+		typ = pointer.Elem()
+	}
+	named, ok := typ.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return nil, false
+	}
+	if !r.relations.Packages().Contains(gocode.PackagePath(named.Obj().Pkg().Path())) {
+		return nil, false
+	}
+	return named, true
+}
+
+// buildFunction は current のパッケージで描画する go/types の関数またはメソッドをモデルに変換する。
+func buildFunction(qualifier *packageQualifier, current gocode.PackagePath, fn *types.Func) *model.Method {
+	signature := fn.Type().(*types.Signature)
+	m := &model.Method{
+		Name:       fn.Name(),
+		Exported:   token.IsExported(fn.Name()),
+		TypeParams: buildTypeParams(signature.TypeParams()),
+	}
+	for i := 0; i < signature.Params().Len(); i++ {
+		p := signature.Params().At(i)
+		typeName := qualifier.goTypeName(current, p.Type())
+		if signature.Variadic() && i == signature.Params().Len()-1 {
+			typeName = "..." + qualifier.goTypeName(current, p.Type().(*types.Slice).Elem())
+		}
+		m.Params = append(m.Params, &model.Param{Name: p.Name(), Type: typeName})
+	}
+	for i := 0; i < signature.Results().Len(); i++ {
+		res := signature.Results().At(i)
+		m.Results = append(m.Results, &model.Result{Name: res.Name(), Type: qualifier.goTypeName(current, res.Type())})
+	}
+	return m
+}
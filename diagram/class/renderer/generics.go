@@ -0,0 +1,112 @@
+package renderer
+
+import (
+	"go/types"
+
+	"github.com/keisuke-m123/goanalyzer/gocode"
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+)
+
+// buildTypeParams は型または関数の型パラメータを制約と合わせてモデルに変換する。
+func buildTypeParams(list *types.TypeParamList) []*model.TypeParam {
+	var typeParams []*model.TypeParam
+	for i := 0; i < list.Len(); i++ {
+		tp := list.At(i)
+		typeParams = append(typeParams, &model.TypeParam{
+			Name:       tp.Obj().Name(),
+			Constraint: goTypeName(tp.Constraint()),
+		})
+	}
+	return typeParams
+}
+
+// buildTypeSet は型集合を持つ interface について、型集合を表す union を宣言順に返す。
+func buildTypeSet(iface *types.Interface) []string {
+	var typeSet []string
+	for i := 0; i < iface.NumEmbeddeds(); i++ {
+		embedded := iface.EmbeddedType(i)
+		if types.IsInterface(embedded) {
+			continue
+		}
+		typeSet = append(typeSet, goTypeName(embedded))
+	}
+	return typeSet
+}
+
+// isGeneric は typ が型パラメータを持つ、インスタンス化されていない型であるかを返す。
+func isGeneric(typ types.Type) bool {
+	named, ok := typ.(*types.Named)
+	return ok && named.TypeParams().Len() > 0 && named.TypeArgs().Len() == 0
+}
+
+// containsTypeParams は typ が型パラメータ、または型引数を持つインスタンス化された型を含むかを返す。
+func containsTypeParams(typ types.Type) bool {
+	switch t := typ.(type) {
+	case *types.TypeParam:
+		return true
+	case *types.Named:
+		return t.TypeArgs().Len() > 0
+	case *types.Pointer:
+		return containsTypeParams(t.Elem())
+	case *types.Slice:
+		return containsTypeParams(t.Elem())
+	case *types.Array:
+		return containsTypeParams(t.Elem())
+	case *types.Chan:
+		return containsTypeParams(t.Elem())
+	case *types.Map:
+		return containsTypeParams(t.Key()) || containsTypeParams(t.Elem())
+	case *types.Signature:
+		return containsTypeParams(t.Params()) || containsTypeParams(t.Results())
+	case *types.Tuple:
+		for i := 0; i < t.Len(); i++ {
+			if containsTypeParams(t.At(i).Type()) {
+				return true
+			}
+		}
+		return false
+	case *types.Struct:
+		for i := 0; i < t.NumFields(); i++ {
+			if containsTypeParams(t.Field(i).Type()) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// typeName は t の型名を返す。
+//
+// gocode は型パラメータと型引数を型名に含めないため、それらを含む型の場合は go/types から型名を生成する。
+func typeName(t *gocode.Type) string {
+	if containsTypeParams(t.GoType()) {
+		return goTypeName(t.GoType())
+	}
+	return t.TypeName().String()
+}
+
+// relativeFullTypeName は pkgSummary のパッケージから見た、パッケージ名付きの t の型名を返す。
+//
+// typeName と同様に、型パラメータと型引数を含む型の場合は go/types から型名を生成する。
+func relativeFullTypeName(pkgSummary *gocode.PackageSummary, t *gocode.Type) string {
+	if !containsTypeParams(t.GoType()) {
+		return t.RelativeFullTypeName().String()
+	}
+	return types.TypeString(t.GoType(), func(pkg *types.Package) string {
+		if gocode.PackagePath(pkg.Path()) == pkgSummary.Path() {
+			return ""
+		}
+		return pkg.Name()
+	})
+}
+
+// instantiatedTypeName は t が型引数を持つインスタンス化された型であれば、型引数を含む型名を返す。
+func instantiatedTypeName(t *gocode.Type) (string, bool) {
+	named, ok := t.GoType().(*types.Named)
+	if !ok || named.TypeArgs().Len() == 0 {
+		return "", false
+	}
+	return goTypeName(named), true
+}
@@ -0,0 +1,258 @@
+package renderer
+
+import (
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/keisuke-m123/goanalyzer/gocode"
+	"github.com/keisuke-m123/godiagramgen/diagram/class/declaration"
+	"github.com/keisuke-m123/godiagramgen/diagram/implementation"
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+)
+
+type (
+	// implementationRenderer は struct と defined type が実装する interface を判定する。
+	//
+	// 判定の対象は読み込んだ全てのパッケージの interface と、標準ライブラリの主要な interface とする。
+	implementationRenderer struct {
+		relations  *gocode.Relations
+		interfaces []*interfaceType
+		// standardInterfaces は標準ライブラリの interface を表す。
+		standardInterfaces []*interfaceType
+		// renderStandardInterfaces は標準ライブラリの interface への関連も描画することを表す。
+		renderStandardInterfaces bool
+	}
+
+	// interfaceType は実装を判定する interface を表す。
+	interfaceType struct {
+		ref         model.NodeRef
+		goInterface *types.Interface
+	}
+)
+
+func newImplementationRenderer(
+	relations *gocode.Relations,
+	declarations *declaration.Declarations,
+	renderStandardInterfaces bool,
+) *implementationRenderer {
+	var interfaces []*interfaceType
+	for _, pkg := range relations.Packages().AsSlice() {
+		for _, iface := range pkg.Detail().Interfaces() {
+			if goInterface, ok := implementableInterface(declarations, iface); ok {
+				interfaces = append(interfaces, &interfaceType{
+					ref:         newNodeRef(iface.PackageSummary(), iface.Name().String()),
+					goInterface: goInterface,
+				})
+			}
+		}
+	}
+	sortInterfaceTypes(interfaces)
+	return &implementationRenderer{
+		relations:                relations,
+		interfaces:               interfaces,
+		standardInterfaces:       newStandardInterfaces(),
+		renderStandardInterfaces: renderStandardInterfaces,
+	}
+}
+
+// implementableInterface は iface が型の実装を判定できる interface である場合に、その go/types の interface を返す。
+// ジェネリックな interface と、型パラメータの制約にのみ使える interface は対象外とする。
+func implementableInterface(declarations *declaration.Declarations, iface *gocode.Interface) (*types.Interface, bool) {
+	named, ok := declarations.Named(iface.PackageSummary().Path(), iface.Name().String())
+	if !ok || isGeneric(named) {
+		return nil, false
+	}
+	goInterface, ok := named.Underlying().(*types.Interface)
+	if !ok || !goInterface.IsMethodSet() || goInterface.NumMethods() == 0 {
+		return nil, false
+	}
+	return goInterface, true
+}
+
+// newStandardInterfaces は実装を判定する標準ライブラリの interface を返す。
+//
+// 読み込んだパッケージが import していなくても判定できるよう、メソッドのシグネチャから interface を組み立てる。
+func newStandardInterfaces() []*interfaceType {
+	// types.Typ[types.Byte] は uint8 と表記されるため、 byte と表記される Universe の型を使う。
+	byteSlice := types.NewSlice(types.Universe.Lookup("byte").Type())
+	errorType := types.Universe.Lookup("error").Type()
+	readWrite := func() *types.Signature {
+		return newSignature(
+			[]*types.Var{types.NewVar(token.NoPos, nil, "p", byteSlice)},
+			[]*types.Var{types.NewVar(token.NoPos, nil, "n", types.Typ[types.Int]), types.NewVar(token.NoPos, nil, "err", errorType)},
+		)
+	}
+	intParams := []*types.Var{types.NewVar(token.NoPos, nil, "i", types.Typ[types.Int]), types.NewVar(token.NoPos, nil, "j", types.Typ[types.Int])}
+
+	interfaces := []*interfaceType{
+		{
+			ref:         model.NodeRef{Name: "error"},
+			goInterface: errorType.Underlying().(*types.Interface),
+		},
+		newStandardInterface("fmt", "Stringer", map[string]*types.Signature{
+			"String": newSignature(nil, []*types.Var{types.NewVar(token.NoPos, nil, "", types.Typ[types.String])}),
+		}),
+		newStandardInterface("io", "Reader", map[string]*types.Signature{"Read": readWrite()}),
+		newStandardInterface("io", "Writer", map[string]*types.Signature{"Write": readWrite()}),
+		newStandardInterface("io", "Closer", map[string]*types.Signature{
+			"Close": newSignature(nil, []*types.Var{types.NewVar(token.NoPos, nil, "", errorType)}),
+		}),
+		newStandardInterface("sort", "Interface", map[string]*types.Signature{
+			"Len":  newSignature(nil, []*types.Var{types.NewVar(token.NoPos, nil, "", types.Typ[types.Int])}),
+			"Less": newSignature(intParams, []*types.Var{types.NewVar(token.NoPos, nil, "", types.Typ[types.Bool])}),
+			"Swap": newSignature(intParams, nil),
+		}),
+	}
+	sortInterfaceTypes(interfaces)
+	return interfaces
+}
+
+func newStandardInterface(pkgPath, name string, methods map[string]*types.Signature) *interfaceType {
+	pkg := types.NewPackage(pkgPath, pkgPath)
+	var funcs []*types.Func
+	for methodName, sig := range methods {
+		funcs = append(funcs, types.NewFunc(token.NoPos, pkg, methodName, sig))
+	}
+	return &interfaceType{
+		ref: model.NodeRef{
+			Package: model.PackageRef{Path: pkgPath, Name: pkgPath},
+			Name:    name,
+		},
+		goInterface: types.NewInterfaceType(funcs, nil).Complete(),
+	}
+}
+
+func newSignature(params, results []*types.Var) *types.Signature {
+	return types.NewSignatureType(nil, nil, nil, types.NewTuple(params...), types.NewTuple(results...), false)
+}
+
+// sortInterfaceTypes は interface をパッケージ名と名前の順、同じ場合はインポートパスの順に並べる。
+func sortInterfaceTypes(interfaces []*interfaceType) {
+	sort.SliceStable(interfaces, func(i, j int) bool {
+		ni := interfaces[i].ref.Package.Name + "." + interfaces[i].ref.Name
+		nj := interfaces[j].ref.Package.Name + "." + interfaces[j].ref.Name
+		if ni != nj {
+			return strings.Compare(ni, nj) < 0
+		}
+		return strings.Compare(interfaces[i].ref.Package.Path, interfaces[j].ref.Package.Path) < 0
+	})
+}
+
+// buildRelations は from の型 typ から、 typ またはそのポインタが実装する interface への関連を返す。
+func (r *implementationRenderer) buildRelations(from model.NodeRef, typ types.Type) []*model.Edge {
+	interfaces := r.interfaces
+	if r.renderStandardInterfaces {
+		interfaces = append(append([]*interfaceType{}, r.interfaces...), r.standardInterfaces...)
+		sortInterfaceTypes(interfaces)
+	}
+	var edges []*model.Edge
+	for _, iface := range interfaces {
+		if _, ok := implements(typ, iface.goInterface); ok {
+			edges = append(edges, &model.Edge{
+				Kind: model.EdgeKindExtension,
+				From: from,
+				To:   iface.ref,
+			})
+		}
+	}
+	return edges
+}
+
+// buildImplementations は読み込んだ全ての interface と、実装された標準ライブラリの interface について、実装する型を返す。
+func (r *implementationRenderer) buildImplementations() []*implementation.Entry {
+	var refs []model.NodeRef
+	var goTypes []types.Type
+	for _, pkg := range r.relations.Packages().AsSlice() {
+		for _, st := range pkg.Detail().Structs() {
+			refs = append(refs, newNodeRef(st.PackageSummary(), st.Name().String()))
+			goTypes = append(goTypes, st.Type().GoType())
+		}
+		for _, dt := range pkg.Detail().DefinedTypes() {
+			refs = append(refs, newNodeRef(dt.PackageSummary(), dt.Name().String()))
+			goTypes = append(goTypes, dt.Type().GoType())
+		}
+	}
+
+	var entries []*implementation.Entry
+	for _, iface := range r.interfaces {
+		entries = append(entries, r.buildEntry(iface, refs, goTypes))
+	}
+	for _, iface := range r.standardInterfaces {
+		if entry := r.buildEntry(iface, refs, goTypes); len(entry.Implementers) > 0 {
+			entries = append(entries, entry)
+		}
+	}
+	implementation.Sort(entries)
+	return entries
+}
+
+func (r *implementationRenderer) buildEntry(
+	iface *interfaceType,
+	refs []model.NodeRef,
+	goTypes []types.Type,
+) *implementation.Entry {
+	entry := &implementation.Entry{Interface: iface.ref}
+	for i, typ := range goTypes {
+		pointerOnly, ok := implements(typ, iface.goInterface)
+		if !ok {
+			continue
+		}
+		entry.Implementers = append(entry.Implementers, &implementation.Implementer{
+			Type:        refs[i],
+			PointerOnly: pointerOnly,
+		})
+	}
+	return entry
+}
+
+// implements は typ またはそのポインタが iface を実装するかを判定する。
+// pointerOnly はポインタのメソッドセットでのみ実装する(ポインタレシーバのメソッドを含む)ことを表す。
+//
+// 型を読み込んだパッケージ毎に go/types の型の同一性が異なるため、メソッドの名前とパッケージを含めた型名のシグネチャで比較する。
+// interface 自身と、インスタンス化されていないジェネリックな型は判定しない。
+func implements(typ types.Type, iface *types.Interface) (pointerOnly bool, ok bool) {
+	if types.IsInterface(typ) || isGeneric(typ) || iface.NumMethods() == 0 {
+		return false, false
+	}
+	if hasMethods(types.NewMethodSet(typ), iface) {
+		return false, true
+	}
+	if hasMethods(types.NewMethodSet(types.NewPointer(typ)), iface) {
+		return true, true
+	}
+	return false, false
+}
+
+func hasMethods(methodSet *types.MethodSet, iface *types.Interface) bool {
+	for i := 0; i < iface.NumMethods(); i++ {
+		m := iface.Method(i)
+		sel := methodSet.Lookup(m.Pkg(), m.Name())
+		if sel == nil || signatureString(sel.Obj().Type()) != signatureString(m.Type()) {
+			return false
+		}
+	}
+	return true
+}
+
+// signatureString はレシーバと引数名を含めず、パッケージをインポートパスで表したシグネチャを返す。
+func signatureString(typ types.Type) string {
+	sig, ok := typ.(*types.Signature)
+	if !ok {
+		return ""
+	}
+	qualifier := func(pkg *types.Package) string { return pkg.Path() }
+	var params, results []string
+	for i := 0; i < sig.Params().Len(); i++ {
+		params = append(params, types.TypeString(sig.Params().At(i).Type(), qualifier))
+	}
+	for i := 0; i < sig.Results().Len(); i++ {
+		results = append(results, types.TypeString(sig.Results().At(i).Type(), qualifier))
+	}
+	variadic := ""
+	if sig.Variadic() {
+		variadic = "..."
+	}
+	return "(" + strings.Join(params, ",") + variadic + ")(" + strings.Join(results, ",") + ")"
+}
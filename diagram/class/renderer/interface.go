@@ -1,67 +1,79 @@
 package renderer
 
 import (
+	"go/types"
 	"sort"
 	"strings"
 
 	"github.com/keisuke-m123/goanalyzer/gocode"
-	"github.com/keisuke-m123/godiagramgen/plantuml"
+	"github.com/keisuke-m123/godiagramgen/diagram/class/declaration"
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
 )
 
 type (
 	interfaceRenderer struct {
 		relations      *gocode.Relations
+		declarations   *declaration.Declarations
 		methodRenderer *methodRenderer
 	}
 )
 
-func newInterfaceRenderer(relations *gocode.Relations) *interfaceRenderer {
+func newInterfaceRenderer(
+	relations *gocode.Relations,
+	declarations *declaration.Declarations,
+	qualifier *packageQualifier,
+) *interfaceRenderer {
 	return &interfaceRenderer{
 		relations:      relations,
-		methodRenderer: newMethodRenderer(),
+		declarations:   declarations,
+		methodRenderer: newMethodRenderer(qualifier),
 	}
 }
 
-func (r *interfaceRenderer) buildInPkg(pkgName gocode.PackageName) *plantuml.ElementStore {
-	elements := plantuml.NewElementStore()
-	for _, name := range r.sortedInterfaceNames(pkgName) {
-		iface, _ := r.relations.Interfaces().Get(pkgName, name)
-		elements.Add(r.buildInterface(iface))
+func (r *interfaceRenderer) buildInPkg(pkgDetail *gocode.PackageDetail) []*model.Node {
+	var nodes []*model.Node
+	for _, iface := range r.sortedInterfaces(pkgDetail) {
+		nodes = append(nodes, r.buildInterface(iface))
 	}
-	return elements
+	return nodes
 }
 
-func (r *interfaceRenderer) buildRelations(pkgName gocode.PackageName) *plantuml.ElementStore {
-	elements := plantuml.NewElementStore()
-	for _, name := range r.sortedInterfaceNames(pkgName) {
-		iface, _ := r.relations.Interfaces().Get(pkgName, name)
-		elements.Add(r.buildCompositions(iface).AsSlice()...)
+func (r *interfaceRenderer) buildRelations(pkgDetail *gocode.PackageDetail) []*model.Edge {
+	var edges []*model.Edge
+	for _, iface := range r.sortedInterfaces(pkgDetail) {
+		edges = append(edges, r.buildCompositions(iface)...)
 	}
-	return elements
+	return edges
 }
 
-func (r *interfaceRenderer) buildInterface(iface *gocode.Interface) plantuml.Element {
-	methods := r.buildMethods(iface)
-	return plantuml.Interface(iface.Name().String(), methods.AsSlice()...)
-}
-
-func (r *interfaceRenderer) buildMethods(iface *gocode.Interface) *plantuml.ElementStore {
-	var orderedFunctions []*gocode.Function
-	methods := iface.Methods()
-	for i := range methods {
-		orderedFunctions = append(orderedFunctions, methods[i])
+func (r *interfaceRenderer) buildInterface(iface *gocode.Interface) *model.Node {
+	node := &model.Node{
+		Kind:    model.NodeKindInterface,
+		Package: newPackageRef(iface.PackageSummary()),
+		Name:    iface.Name().String(),
+		Methods: r.methodRenderer.buildMethods(iface.PackageSummary().Path(), iface.Methods()),
 	}
-	sort.Slice(orderedFunctions, func(i, j int) bool {
-		return strings.Compare(orderedFunctions[i].Name().String(), orderedFunctions[j].Name().String()) < 0
-	})
 
-	return r.methodRenderer.buildMethods(orderedFunctions)
+	named, ok := r.declarations.Named(iface.PackageSummary().Path(), iface.Name().String())
+	if !ok {
+		return node
+	}
+	node.TypeParams = buildTypeParams(named.TypeParams())
+	if goInterface, ok := named.Underlying().(*types.Interface); ok && !goInterface.IsMethodSet() {
+		node.Kind = model.NodeKindConstraint
+		node.TypeSet = buildTypeSet(goInterface)
+	}
+	return node
 }
 
-func (r *interfaceRenderer) buildCompositions(iface *gocode.Interface) *plantuml.ElementStore {
+func (r *interfaceRenderer) buildCompositions(iface *gocode.Interface) []*model.Edge {
 	var orderedEmbeds []*gocode.Embed
 	embeds := iface.Embeds()
 	for i := range embeds {
+		// 型集合を表す union などの interface 以外の要素は TypeSet として描画する。
+		if !types.IsInterface(embeds[i].Type().GoType()) {
+			continue
+		}
 		orderedEmbeds = append(orderedEmbeds, embeds[i])
 	}
 	sort.Slice(orderedEmbeds, func(i, j int) bool {
@@ -71,26 +83,22 @@ func (r *interfaceRenderer) buildCompositions(iface *gocode.Interface) *plantuml
 		) < 0
 	})
 
-	elements := plantuml.NewElementStore()
+	var edges []*model.Edge
 	for i := range orderedEmbeds {
 		e := orderedEmbeds[i]
-		elements.Add(plantuml.Relation(
-			plantuml.NewRelationTargetWithNamespace(iface.PackageSummary().Name().String(), iface.Name().String()),
-			plantuml.NewRelationTargetWithNamespace(e.Type().PackageSummary().Name().String(), e.Type().TypeName().String()),
-			plantuml.RelationTypeComposition,
-		))
+		edges = append(edges, &model.Edge{
+			Kind: model.EdgeKindComposition,
+			From: newNodeRef(iface.PackageSummary(), iface.Name().String()),
+			To:   newNodeRef(e.Type().PackageSummary(), e.Type().TypeName().String()),
+		})
 	}
-	return elements
+	return edges
 }
 
-func (r *interfaceRenderer) sortedInterfaceNames(pkgName gocode.PackageName) []gocode.InterfaceName {
-	interfaces := r.relations.Interfaces().PackageInterfaces(pkgName)
-	var names []gocode.InterfaceName
-	for i := range interfaces {
-		names = append(names, interfaces[i].Name())
-	}
-	sort.Slice(names, func(i, j int) bool {
-		return strings.Compare(names[i].String(), names[j].String()) < 0
+func (r *interfaceRenderer) sortedInterfaces(pkgDetail *gocode.PackageDetail) []*gocode.Interface {
+	interfaces := append([]*gocode.Interface{}, pkgDetail.Interfaces()...)
+	sort.Slice(interfaces, func(i, j int) bool {
+		return strings.Compare(interfaces[i].Name().String(), interfaces[j].Name().String()) < 0
 	})
-	return names
+	return interfaces
 }
@@ -1,45 +1,44 @@
 package renderer
 
 import (
-	"unicode"
+	"go/token"
+	"sort"
+	"strings"
 
 	"github.com/keisuke-m123/goanalyzer/gocode"
-	"github.com/keisuke-m123/godiagramgen/plantuml"
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
 )
 
 type (
-	methodRenderer struct{}
+	methodRenderer struct {
+		qualifier *packageQualifier
+	}
 )
 
-func newMethodRenderer() *methodRenderer {
-	return &methodRenderer{}
+func newMethodRenderer(qualifier *packageQualifier) *methodRenderer {
+	return &methodRenderer{qualifier: qualifier}
 }
 
-func (mr *methodRenderer) buildMethods(functions []*gocode.Function) *plantuml.ElementStore {
-	elements := plantuml.NewElementStore()
-	for _, method := range functions {
-		accessModifier := plantuml.AccessModifierPublic
-		if unicode.IsLower(rune(method.Name().String()[0])) {
-			accessModifier = plantuml.AccessModifierPrivate
+// buildMethods は current のパッケージで宣言された型のメソッド functions を名前順に並べ替えてモデルに変換する。
+func (mr *methodRenderer) buildMethods(current gocode.PackagePath, functions []*gocode.Function) []*model.Method {
+	orderedFunctions := append([]*gocode.Function{}, functions...)
+	sort.Slice(orderedFunctions, func(i, j int) bool {
+		return strings.Compare(orderedFunctions[i].Name().String(), orderedFunctions[j].Name().String()) < 0
+	})
+
+	var methods []*model.Method
+	for _, function := range orderedFunctions {
+		m := &model.Method{
+			Name:     function.Name().String(),
+			Exported: token.IsExported(function.Name().String()),
 		}
-		params := make(plantuml.Params, 0)
-		for _, p := range method.Parameters() {
-			params = append(params, plantuml.Param{Name: p.Name(), Type: p.Type().TypeName().String()})
+		for _, p := range function.Parameters() {
+			m.Params = append(m.Params, &model.Param{Name: p.Name(), Type: mr.qualifier.typeName(current, p.Type())})
 		}
-
-		returnValues := make(plantuml.ReturnValues, 0)
-		for _, r := range method.ReturnValues() {
-			returnValues = append(returnValues, plantuml.ReturnValue{
-				Type: r.Type().TypeName().String(),
-			})
+		for _, r := range function.ReturnValues() {
+			m.Results = append(m.Results, &model.Result{Name: r.Name(), Type: mr.qualifier.typeName(current, r.Type())})
 		}
-
-		elements.Add(plantuml.Method(
-			accessModifier,
-			method.Name().String(),
-			params,
-			returnValues,
-		))
+		methods = append(methods, m)
 	}
-	return elements
+	return methods
 }
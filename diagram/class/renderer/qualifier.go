@@ -0,0 +1,98 @@
+package renderer
+
+import (
+	"go/types"
+
+	"github.com/keisuke-m123/goanalyzer/gocode"
+)
+
+type (
+	// packageQualifier はフィールドや引数、戻り値の型名を修飾するパッケージ名を決める。
+	//
+	// 他のパッケージの型のパッケージ名が、描画するパッケージ自体や読み込んだ他のパッケージのパッケージ名と重複する場合は、
+	// 同じパッケージの型や同名のパッケージの型と区別できるよう、インポートパスで修飾する。
+	packageQualifier struct {
+		// namesByPath は読み込んだパッケージのインポートパスとパッケージ名の対応。
+		namesByPath map[string]string
+		// pathsByName はパッケージ名毎の、読み込んだパッケージのインポートパスの集合。
+		pathsByName map[string]map[string]struct{}
+	}
+)
+
+func newPackageQualifier(relations *gocode.Relations) *packageQualifier {
+	q := &packageQualifier{
+		namesByPath: make(map[string]string),
+		pathsByName: make(map[string]map[string]struct{}),
+	}
+	for _, pkg := range relations.Packages().AsSlice() {
+		path, name := pkg.Summary().Path().String(), pkg.Summary().Name().String()
+		q.namesByPath[path] = name
+		if _, ok := q.pathsByName[name]; !ok {
+			q.pathsByName[name] = make(map[string]struct{})
+		}
+		q.pathsByName[name][path] = struct{}{}
+	}
+	return q
+}
+
+// ambiguous は current のパッケージから見て、 pkg のパッケージ名だけでは型のパッケージを特定できないかを返す。
+func (q *packageQualifier) ambiguous(current gocode.PackagePath, pkg *types.Package) bool {
+	if pkg.Path() == current.String() {
+		return false
+	}
+	if pkg.Name() == q.namesByPath[current.String()] {
+		return true
+	}
+	for path := range q.pathsByName[pkg.Name()] {
+		if path != pkg.Path() {
+			return true
+		}
+	}
+	return false
+}
+
+// qualifiedTypeName は typ がパッケージ名の重複する他のパッケージの型を含む場合に、その型をインポートパスで修飾した型名を返す。
+// 含まない場合は ok に false を返す。
+//
+// qualifyByName を指定した場合は、重複しない他のパッケージの型をパッケージ名で修飾する。
+func (q *packageQualifier) qualifiedTypeName(current gocode.PackagePath, typ types.Type, qualifyByName bool) (name string, ok bool) {
+	name = types.TypeString(typ, func(pkg *types.Package) string {
+		switch {
+		case pkg.Path() == current.String():
+			return ""
+		case q.ambiguous(current, pkg):
+			ok = true
+			return pkg.Path()
+		case qualifyByName:
+			return pkg.Name()
+		default:
+			return ""
+		}
+	})
+	return name, ok
+}
+
+// typeName は current のパッケージから見た t の型名を返す。パッケージ名が重複する型のみインポートパスで修飾する。
+func (q *packageQualifier) typeName(current gocode.PackagePath, t *gocode.Type) string {
+	if name, ok := q.qualifiedTypeName(current, t.GoType(), false); ok {
+		return name
+	}
+	return typeName(t)
+}
+
+// relativeFullTypeName は current のパッケージから見た、パッケージ名付きの t の型名を返す。
+// パッケージ名が重複する型はインポートパスで修飾する。
+func (q *packageQualifier) relativeFullTypeName(current *gocode.PackageSummary, t *gocode.Type) string {
+	if name, ok := q.qualifiedTypeName(current.Path(), t.GoType(), true); ok {
+		return name
+	}
+	return relativeFullTypeName(current, t)
+}
+
+// goTypeName は current のパッケージから見た typ の型名を返す。パッケージ名が重複する型のみインポートパスで修飾する。
+func (q *packageQualifier) goTypeName(current gocode.PackagePath, typ types.Type) string {
+	if name, ok := q.qualifiedTypeName(current, typ, false); ok {
+		return name
+	}
+	return goTypeName(typ)
+}
@@ -1,11 +1,17 @@
 package renderer
 
 import (
+	"go/types"
 	"regexp"
 	"sort"
 	"strings"
 
 	"github.com/keisuke-m123/goanalyzer/gocode"
+	"github.com/keisuke-m123/godiagramgen/diagram/class/declaration"
+	"github.com/keisuke-m123/godiagramgen/diagram/emitter"
+	"github.com/keisuke-m123/godiagramgen/diagram/filter"
+	"github.com/keisuke-m123/godiagramgen/diagram/implementation"
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
 	"github.com/keisuke-m123/godiagramgen/plantuml"
 )
 
@@ -14,70 +20,223 @@ type RenderingOptions struct {
 	Notes                  string
 	Theme                  string
 	RenderExternalPackages bool
+	// ShortPackageNames はパッケージ名が重複しない限り、 namespace にインポートパスではなくパッケージ名を使う。
+	ShortPackageNames bool
+	// RenderFunctions はパッケージレベルの関数と、コンストラクタから生成する型への関連を描画する。
+	RenderFunctions bool
+	// RenderConstants は defined type を型とする exported な定数を、その defined type の値として描画する。
+	RenderConstants bool
+	// RenderVariables はパッケージレベルの変数を描画する。
+	RenderVariables bool
+	// RenderMethodDependencies はメソッドの引数と戻り値に現れる型への依存を描画する。
+	RenderMethodDependencies bool
+	// RenderTags は struct のフィールドの struct tag を描画する。
+	RenderTags bool
+	// TagKeys は描画する struct tag のキーを絞り込む。指定した場合は RenderTags を指定しなくても描画する。
+	TagKeys []string
+	// RenderDocs は型とフィールド、メソッドのドキュメントコメントを描画する。
+	RenderDocs bool
+	// APISurface は exported な型とメンバーのみを描画し、 unexported な型の埋め込みによって昇格されるメンバーを描画する。
+	APISurface bool
+	// Filter は描画するパッケージと型を絞り込む。 nil の場合は絞り込まない。
+	Filter *filter.Filter
+	// Focus は pkg.TypeName 形式で指定された型と、その型から FocusDepth 回以内の関連で辿れる型のみを描画する。
+	Focus          string
+	FocusDepth     int
+	FocusDirection model.FocusDirection
 }
 
 type Renderer struct {
-	relations           *gocode.Relations
-	renderingOptions    *RenderingOptions
-	structRenderer      *structRenderer
-	interfaceRenderer   *interfaceRenderer
-	definedTypeRenderer *definedTypeRenderer
-	aliasRenderer       *aliasRenderer
+	relations              *gocode.Relations
+	renderingOptions       *RenderingOptions
+	implementationRenderer *implementationRenderer
+	structRenderer         *structRenderer
+	interfaceRenderer      *interfaceRenderer
+	definedTypeRenderer    *definedTypeRenderer
+	aliasRenderer          *aliasRenderer
+	functionRenderer       *functionRenderer
+	variableRenderer       *variableRenderer
+	dependencyRenderer     *dependencyRenderer
+	annotationRenderer     *annotationRenderer
+	erdRenderer            *erdRenderer
 }
 
-func NewRenderer(relations *gocode.Relations, options *RenderingOptions) *Renderer {
+// NewRenderer は relations を描画する Renderer を生成する。
+//
+// declarations は relations と同じディレクトリから読み込んだものを指定する。
+func NewRenderer(
+	relations *gocode.Relations,
+	declarations *declaration.Declarations,
+	options *RenderingOptions,
+) *Renderer {
+	// 標準ライブラリの interface は他のパッケージの型と同様に、外部パッケージを描画する場合のみ関連を描画する。
+	implementationRenderer := newImplementationRenderer(relations, declarations, options.RenderExternalPackages)
+	qualifier := newPackageQualifier(relations)
+	structRenderer := newStructRenderer(
+		relations,
+		declarations,
+		implementationRenderer,
+		qualifier,
+		options.RenderExternalPackages,
+		options.APISurface,
+	)
 	return &Renderer{
-		relations:           relations,
-		renderingOptions:    options,
-		structRenderer:      newStructRenderer(relations, options.RenderExternalPackages),
-		interfaceRenderer:   newInterfaceRenderer(relations),
-		definedTypeRenderer: newDefinedTypeRenderer(relations),
-		aliasRenderer:       newAliasRender(relations),
+		relations:              relations,
+		renderingOptions:       options,
+		implementationRenderer: implementationRenderer,
+		structRenderer:         structRenderer,
+		interfaceRenderer:      newInterfaceRenderer(relations, declarations, qualifier),
+		definedTypeRenderer:    newDefinedTypeRenderer(relations, declarations, implementationRenderer, qualifier, options.RenderConstants),
+		aliasRenderer:          newAliasRender(relations),
+		functionRenderer:       newFunctionRenderer(relations, declarations, qualifier),
+		variableRenderer:       newVariableRenderer(declarations, qualifier),
+		dependencyRenderer:     newDependencyRenderer(relations, options.RenderExternalPackages),
+		annotationRenderer:     newAnnotationRenderer(declarations, options.RenderTags, options.TagKeys, options.RenderDocs),
+		erdRenderer:            newERDRenderer(declarations, structRenderer),
 	}
 }
 
-func (r *Renderer) Render() *plantuml.Result {
-	elements := plantuml.NewElementStore()
-	if r.renderingOptions.Theme != "" {
-		elements.Add(plantuml.Theme(r.renderingOptions.Theme))
+// Build は解析結果から出力形式に依存しない図のモデルを生成する。
+//
+// RenderingOptions.Filter で絞り込んだ後、 RenderingOptions.Focus が指定されている場合は注目する型の周辺のみに絞り込む。
+func (r *Renderer) Build() *model.Diagram {
+	d := r.buildFiltered()
+	if r.renderingOptions.Focus == "" {
+		return d
 	}
-	if r.renderingOptions.Title != "" {
-		elements.Add(plantuml.Title(r.renderingOptions.Title))
+	ref, err := r.resolveFocus(d)
+	if err != nil {
+		// ValidateFocus で検証済みのため、ここでは絞り込まずに返す。
+		return d
 	}
-	if note := strings.TrimSpace(r.renderingOptions.Notes); note != "" {
-		elements.Add(plantuml.Legend(r.renderingOptions.Notes))
+	return d.Focus(ref, r.renderingOptions.FocusDepth, r.renderingOptions.FocusDirection)
+}
+
+func (r *Renderer) buildFiltered() *model.Diagram {
+	d := r.buildAll()
+	if r.renderingOptions.APISurface {
+		d = filter.NewAPISurfaceFilter().Apply(d)
 	}
+	if r.renderingOptions.Filter == nil {
+		return d
+	}
+	return r.renderingOptions.Filter.Apply(d)
+}
 
-	var packageNames []gocode.PackageName
-	for _, pkg := range r.relations.Packages().AsSlice() {
-		packageNames = append(packageNames, pkg.Summary().Name())
+func (r *Renderer) buildAll() *model.Diagram {
+	d := &model.Diagram{
+		Title: r.renderingOptions.Title,
+		Notes: r.renderingOptions.Notes,
+		Theme: r.renderingOptions.Theme,
+
+		ShortPackageNames: r.renderingOptions.ShortPackageNames,
+	}
+	for _, pkg := range r.sortedPackages() {
+		d.Packages = append(d.Packages, r.buildPackage(pkg))
 	}
-	sort.Slice(packageNames, func(i, j int) bool {
-		return strings.Compare(packageNames[i].String(), packageNames[j].String()) < 0
+	return d
+}
+
+// Implementations は読み込んだ全ての interface と、実装された標準ライブラリの interface について、
+// 実装する struct と defined type を返す。 RenderingOptions の絞り込みは適用しない。
+func (r *Renderer) Implementations() []*implementation.Entry {
+	return r.implementationRenderer.buildImplementations()
+}
+
+// Schema は db, gorm, sql の struct tag を持つ struct をテーブルとした、エンティティ関連図のモデルを返す。
+// RenderingOptions は Title のみを参照する。
+func (r *Renderer) Schema() *model.Schema {
+	schema := r.erdRenderer.buildSchema(r.sortedPackages())
+	schema.Title = r.renderingOptions.Title
+	return schema
+}
+
+// RenderWith は Build で生成したモデルを e で変換して返す。
+func (r *Renderer) RenderWith(e emitter.Emitter) string {
+	return e.Emit(r.Build())
+}
+
+// Render は解析結果を PlantUML 形式で返す。
+func (r *Renderer) Render() *plantuml.Result {
+	return emitter.NewPlantUMLClassEmitter().EmitResult(r.Build())
+}
+
+// RenderPlantUML は Render の結果を文字列で返す。
+func (r *Renderer) RenderPlantUML() string {
+	return r.RenderWith(emitter.NewPlantUMLClassEmitter())
+}
+
+func (r *Renderer) RenderMermaid() string {
+	return r.RenderWith(emitter.NewMermaidClassEmitter())
+}
+
+func (r *Renderer) sortedPackages() []*gocode.Package {
+	packages := r.relations.Packages().AsSlice()
+	sort.Slice(packages, func(i, j int) bool {
+		si, sj := packages[i].Summary(), packages[j].Summary()
+		if si.Name() != sj.Name() {
+			return strings.Compare(si.Name().String(), sj.Name().String()) < 0
+		}
+		return strings.Compare(si.Path().String(), sj.Path().String()) < 0
 	})
-	for _, pkgName := range packageNames {
-		elements.Add(r.buildPackage(pkgName).AsSlice()...)
+	return packages
+}
+
+func (r *Renderer) buildPackage(pkg *gocode.Package) *model.Package {
+	pkgDetail := pkg.Detail()
+
+	p := &model.Package{PackageRef: newPackageRef(pkg.Summary())}
+	p.Nodes = append(p.Nodes, r.structRenderer.buildInPkg(pkgDetail)...)
+	p.Nodes = append(p.Nodes, r.interfaceRenderer.buildInPkg(pkgDetail)...)
+	p.Nodes = append(p.Nodes, r.definedTypeRenderer.buildInPkg(pkgDetail)...)
+	p.Nodes = append(p.Nodes, r.aliasRenderer.buildInPkg(pkgDetail)...)
+	if r.renderingOptions.RenderFunctions {
+		p.Nodes = append(p.Nodes, r.functionRenderer.buildInPkg(pkg.Summary())...)
+	}
+	if r.renderingOptions.RenderVariables {
+		p.Nodes = append(p.Nodes, r.variableRenderer.buildInPkg(pkg.Summary())...)
+	}
+
+	p.Edges = append(p.Edges, r.structRenderer.buildStructRelations(pkgDetail)...)
+	p.Edges = append(p.Edges, r.interfaceRenderer.buildRelations(pkgDetail)...)
+	p.Edges = append(p.Edges, r.definedTypeRenderer.buildRelations(pkgDetail)...)
+	p.Edges = append(p.Edges, r.aliasRenderer.buildRelations(pkgDetail)...)
+	if r.renderingOptions.RenderFunctions {
+		p.Edges = append(p.Edges, r.functionRenderer.buildRelations(pkg.Summary())...)
+	}
+	if r.renderingOptions.RenderMethodDependencies {
+		p.Edges = append(p.Edges, r.dependencyRenderer.buildRelations(pkgDetail)...)
 	}
-	elements.Add(r.aliasRenderer.buildTypeAliases().AsSlice()...)
-	return plantuml.PlantUML(elements.AsSlice()...)
+	r.annotationRenderer.annotate(p)
+
+	return p
 }
 
-func (r *Renderer) buildPackage(pkgName gocode.PackageName) *plantuml.ElementStore {
-	structs := r.structRenderer.buildInPkg(pkgName)
-	interfaces := r.interfaceRenderer.buildInPkg(pkgName)
-	definedTypes := r.definedTypeRenderer.buildInPkg(pkgName)
+func newPackageRef(pkgSummary *gocode.PackageSummary) model.PackageRef {
+	return model.PackageRef{
+		Path: pkgSummary.Path().String(),
+		Name: pkgSummary.Name().String(),
+	}
+}
 
-	elements := plantuml.NewElementStore()
-	elements.Add(plantuml.Namespace(
-		pkgName.String(),
-		structs.Merge(interfaces).Merge(definedTypes).AsSlice()...,
-	))
+func newNodeRef(pkgSummary *gocode.PackageSummary, name string) model.NodeRef {
+	return model.NodeRef{
+		Package: newPackageRef(pkgSummary),
+		Name:    name,
+	}
+}
 
-	elements.Add(r.structRenderer.buildStructRelations(pkgName).AsSlice()...)
-	elements.Add(r.interfaceRenderer.buildRelations(pkgName).AsSlice()...)
-	elements.Add(r.definedTypeRenderer.buildRelations(pkgName).AsSlice()...)
+// goTypeName は gocode.TypeName と同様に、パッケージ名を含めずに go/types の型名を返す。
+func goTypeName(typ types.Type) string {
+	return types.TypeString(typ, func(*types.Package) string { return "" })
+}
 
-	return elements
+// typeKey はパッケージを読み込んだ単位によらず型を識別するため、インポートパスを含めた型名を返す。
+func typeKey(named *types.Named) string {
+	if named.Obj().Pkg() == nil {
+		return named.Obj().Name()
+	}
+	return named.Obj().Pkg().Path() + "." + named.Obj().Name()
 }
 
 func generateRenamedName(currentName string) string {
@@ -1,99 +1,108 @@
 package renderer
 
 import (
+	"fmt"
+	"go/token"
+	"go/types"
 	"sort"
 	"strings"
-	"unicode"
 
 	"github.com/keisuke-m123/goanalyzer/gocode"
-	"github.com/keisuke-m123/godiagramgen/plantuml"
+	"github.com/keisuke-m123/godiagramgen/diagram/class/declaration"
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
 )
 
 type (
 	structRenderer struct {
 		relations              *gocode.Relations
+		declarations           *declaration.Declarations
+		implementationRenderer *implementationRenderer
 		methodRenderer         *methodRenderer
+		qualifier              *packageQualifier
 		renderExternalPackages bool
+		// apiSurface は unexported な型の埋め込みによって昇格される exported なフィールドとメソッドを描画し、
+		// unexported なフィールドからの関連を描画しない。
+		apiSurface bool
 	}
 )
 
-func newStructRenderer(relations *gocode.Relations, renderExternalPackages bool) *structRenderer {
+func newStructRenderer(
+	relations *gocode.Relations,
+	declarations *declaration.Declarations,
+	implementationRenderer *implementationRenderer,
+	qualifier *packageQualifier,
+	renderExternalPackages bool,
+	apiSurface bool,
+) *structRenderer {
 	return &structRenderer{
 		relations:              relations,
-		methodRenderer:         newMethodRenderer(),
+		declarations:           declarations,
+		implementationRenderer: implementationRenderer,
+		methodRenderer:         newMethodRenderer(qualifier),
+		qualifier:              qualifier,
 		renderExternalPackages: renderExternalPackages,
+		apiSurface:             apiSurface,
 	}
 }
 
-func (r *structRenderer) sortedStructNames(pkgName gocode.PackageName) []gocode.StructName {
-	s := r.relations.Structs().PackageStructs(pkgName)
-	var names []gocode.StructName
-	for i := range s {
-		names = append(names, s[i].Name())
-	}
-	sort.Slice(names, func(i, j int) bool {
-		return strings.Compare(names[i].String(), names[j].String()) < 0
+func (r *structRenderer) sortedStructs(pkgDetail *gocode.PackageDetail) []*gocode.Struct {
+	structs := append([]*gocode.Struct{}, pkgDetail.Structs()...)
+	sort.Slice(structs, func(i, j int) bool {
+		return strings.Compare(structs[i].Name().String(), structs[j].Name().String()) < 0
 	})
-	return names
+	return structs
 }
 
-func (r *structRenderer) buildStructRelations(pkgName gocode.PackageName) *plantuml.ElementStore {
-	elements := plantuml.NewElementStore()
-	for _, name := range r.sortedStructNames(pkgName) {
-		st, _ := r.relations.Structs().Get(pkgName, name)
-		elements.Add(r.buildStructRelation(st).AsSlice()...)
+func (r *structRenderer) buildStructRelations(pkgDetail *gocode.PackageDetail) []*model.Edge {
+	var edges []*model.Edge
+	for _, st := range r.sortedStructs(pkgDetail) {
+		edges = append(edges, r.buildStructRelation(st)...)
 	}
-	return elements
+	return edges
 }
 
-func (r *structRenderer) buildInPkg(pkgName gocode.PackageName) *plantuml.ElementStore {
-	elements := plantuml.NewElementStore()
-	for _, name := range r.sortedStructNames(pkgName) {
-		s, _ := r.relations.Structs().Get(pkgName, name)
-		elements.Add(r.buildElementStructure(s))
+func (r *structRenderer) buildInPkg(pkgDetail *gocode.PackageDetail) []*model.Node {
+	var nodes []*model.Node
+	for _, st := range r.sortedStructs(pkgDetail) {
+		nodes = append(nodes, r.buildElementStructure(st))
 	}
-	return elements
+	return nodes
 }
 
-func (r *structRenderer) buildElementStructure(st *gocode.Struct) plantuml.Element {
-	fields := r.buildStructFields(st)
-	methods := r.buildStructMethods(st)
-	color, _ := plantuml.ParseHexColor("#7FFFD4")
-	return plantuml.ClassWithOption(
-		st.Name().String(),
-		plantuml.ClassOptions{
-			Spot: plantuml.Spot{
-				Name:  'S',
-				Color: color,
-			},
-		},
-		fields.Merge(methods).AsSlice()...,
-	)
+func (r *structRenderer) buildElementStructure(st *gocode.Struct) *model.Node {
+	node := &model.Node{
+		Kind:    model.NodeKindStruct,
+		Package: newPackageRef(st.PackageSummary()),
+		Name:    st.Name().String(),
+		Fields:  r.buildStructFields(st),
+		Methods: r.buildStructMethods(st),
+	}
+	if named, ok := st.Type().GoType().(*types.Named); ok {
+		node.TypeParams = buildTypeParams(named.TypeParams())
+	}
+	return node
 }
 
-func (r *structRenderer) buildStructRelation(st *gocode.Struct) *plantuml.ElementStore {
-	elements := plantuml.NewElementStore()
-	elements.Add(r.buildCompositions(st).AsSlice()...)
-	elements.Add(r.buildExtends(st).AsSlice()...)
-	elements.Add(r.buildAggregations(st).AsSlice()...)
-	return elements
+func (r *structRenderer) buildStructRelation(st *gocode.Struct) []*model.Edge {
+	var edges []*model.Edge
+	edges = append(edges, r.buildCompositions(st)...)
+	edges = append(edges, r.buildExtends(st)...)
+	edges = append(edges, r.buildAggregations(st)...)
+	return edges
 }
 
-func (r *structRenderer) buildCompositions(s *gocode.Struct) *plantuml.ElementStore {
-	elements := plantuml.NewElementStore()
+func (r *structRenderer) buildCompositions(s *gocode.Struct) []*model.Edge {
+	var edges []*model.Edge
 	for _, f := range s.Fields() {
 		if f.Embedded() {
-			elements.Add(r.buildStructCompositionFromField(s, f).AsSlice()...)
+			edges = append(edges, r.buildStructCompositionFromField(s, f)...)
 		}
 	}
-	return elements
+	return edges
 }
 
-func (r *structRenderer) buildStructCompositionFromField(structure *gocode.Struct, f *gocode.Field) *plantuml.ElementStore {
-	pkgName := structure.PackageSummary().Name().String()
-	structName := structure.Name().String()
-	elements := plantuml.NewElementStore()
-
+func (r *structRenderer) buildStructCompositionFromField(structure *gocode.Struct, f *gocode.Field) []*model.Edge {
+	var edges []*model.Edge
 	uniqueTypeNameSet := make(map[string]struct{})
 	for _, fType := range append(f.Type().FundamentalTypes(), f.Type()) {
 		if _, ok := uniqueTypeNameSet[fType.RelativeFullTypeName().String()]; ok {
@@ -101,45 +110,62 @@ func (r *structRenderer) buildStructCompositionFromField(structure *gocode.Struc
 		}
 		uniqueTypeNameSet[fType.RelativeFullTypeName().String()] = struct{}{}
 
-		elements.Add(plantuml.Relation(
-			plantuml.NewRelationTargetWithNamespace(pkgName, structName),
-			plantuml.NewRelationTargetWithNamespace(fType.PackageSummary().Name().String(), fType.TypeName().String()),
-			plantuml.RelationTypeComposition,
-		))
+		edges = append(edges, &model.Edge{
+			Kind:             model.EdgeKindComposition,
+			From:             newNodeRef(structure.PackageSummary(), structure.Name().String()),
+			To:               newNodeRef(fType.PackageSummary(), fType.TypeName().String()),
+			FromMultiplicity: "1",
+			ToMultiplicity:   fieldMultiplicity(f.Type().GoType(), fType.GoType()),
+		})
 	}
-	return elements
+	return edges
 }
 
-func (r *structRenderer) buildAggregations(st *gocode.Struct) *plantuml.ElementStore {
-	var orderedFundamentalTypes []*gocode.Type
+func (r *structRenderer) buildAggregations(st *gocode.Struct) []*model.Edge {
+	type aggregation struct {
+		field *gocode.Field
+		fType *gocode.Type
+	}
+	var aggregations []aggregation
 	for _, f := range st.Fields() {
-		if !f.Embedded() {
-			orderedFundamentalTypes = append(orderedFundamentalTypes, f.Type().FundamentalTypes()...)
+		if f.Embedded() || (r.apiSurface && !f.Exported()) {
+			continue
+		}
+		uniqueTypeNameSet := make(map[string]struct{})
+		for _, fType := range f.Type().FundamentalTypes() {
+			if _, ok := uniqueTypeNameSet[fType.RelativeFullTypeName().String()]; ok {
+				continue
+			}
+			uniqueTypeNameSet[fType.RelativeFullTypeName().String()] = struct{}{}
+			aggregations = append(aggregations, aggregation{field: f, fType: fType})
 		}
 	}
-	sort.Slice(orderedFundamentalTypes, func(i, j int) bool {
-		return strings.Compare(orderedFundamentalTypes[i].TypeName().String(), orderedFundamentalTypes[j].TypeName().String()) < 0
+	sort.SliceStable(aggregations, func(i, j int) bool {
+		return strings.Compare(aggregations[i].fType.TypeName().String(), aggregations[j].fType.TypeName().String()) < 0
 	})
 
-	elements := plantuml.NewElementStore()
-	pkgName := st.PackageSummary().Name().String()
-	structName := st.Name().String()
-
-	for _, fType := range orderedFundamentalTypes {
-		if fType.Builtin() || !r.isRenderingAggregation(fType) {
+	var edges []*model.Edge
+	for _, a := range aggregations {
+		if a.fType.Builtin() || !r.isRenderingAggregation(a.fType) {
 			continue
 		}
 
-		fTypePkgName := fType.PackageSummary().Name().String()
-		fTypeName := removePointerFromName(fType.TypeName().String())
-		elements.Add(plantuml.Relation(
-			plantuml.NewRelationTargetWithNamespace(fTypePkgName, fTypeName),
-			plantuml.NewRelationTargetWithNamespace(pkgName, structName),
-			plantuml.RelationTypeAggregation,
-		))
+		// フィールド名を役割として添え、インスタンス化された型はジェネリックな型の宣言への関連として型引数を続ける。
+		label := a.field.Name().String()
+		if instantiated, ok := instantiatedTypeName(a.fType); ok {
+			label = fmt.Sprintf("%s %s", label, instantiated)
+		}
+		edges = append(edges, &model.Edge{
+			Kind:             model.EdgeKindAggregation,
+			From:             newNodeRef(st.PackageSummary(), st.Name().String()),
+			To:               newNodeRef(a.fType.PackageSummary(), removePointerFromName(a.fType.TypeName().String())),
+			Label:            label,
+			FromMultiplicity: "1",
+			ToMultiplicity:   fieldMultiplicity(a.field.Type().GoType(), a.fType.GoType()),
+		})
 	}
 
-	return elements
+	return edges
 }
 
 // isRenderingAggregation 外部パッケージのTypeをAggregationとして描画するかを判定する。
@@ -147,46 +173,165 @@ func (r *structRenderer) isRenderingAggregation(fType *gocode.Type) bool {
 	return r.renderExternalPackages || r.relations.Packages().Contains(fType.PackageSummary().Path())
 }
 
-func (r *structRenderer) buildExtends(st *gocode.Struct) *plantuml.ElementStore {
-	elements := plantuml.NewElementStore()
-	pkgName := st.PackageSummary().Name().String()
-	structName := st.Name().String()
-	for _, name := range st.ImplementInterfaces().PackageInterfaceNames() {
-		elements.Add(plantuml.Relation(
-			plantuml.NewRelationTargetWithNamespace(pkgName, structName),
-			plantuml.NewRelationTarget(name.String()),
-			plantuml.RelationTypeExtension,
-		))
+func (r *structRenderer) buildExtends(st *gocode.Struct) []*model.Edge {
+	return r.implementationRenderer.buildRelations(
+		newNodeRef(st.PackageSummary(), st.Name().String()),
+		st.Type().GoType(),
+	)
+}
+
+func (r *structRenderer) buildStructFields(st *gocode.Struct) []*model.Field {
+	ownNameSet := make(map[string]struct{})
+	for _, field := range st.Fields() {
+		ownNameSet[field.Name().String()] = struct{}{}
+	}
+
+	var fields []*model.Field
+	for _, field := range st.Fields() {
+		fields = append(fields, &model.Field{
+			Name:     field.Name().String(),
+			Type:     r.qualifier.relativeFullTypeName(st.PackageSummary(), field.Type()),
+			Exported: token.IsExported(field.Name().String()),
+			Embedded: field.Embedded(),
+		})
+		if r.apiSurface && field.Embedded() && !field.Exported() {
+			for _, promoted := range r.buildPromotedFields(st.PackageSummary().Path(), field.Type().GoType()) {
+				if _, ok := ownNameSet[promoted.Name]; ok {
+					continue
+				}
+				ownNameSet[promoted.Name] = struct{}{}
+				fields = append(fields, promoted)
+			}
+		}
 	}
-	return elements
+	return fields
 }
 
-func (r *structRenderer) buildStructMethods(st *gocode.Struct) *plantuml.ElementStore {
-	var orderedFunctions []*gocode.Function
-	methods := st.Methods()
-	for i := range methods {
-		orderedFunctions = append(orderedFunctions, methods[i])
+// buildPromotedFields は埋め込まれた typ から昇格される exported なフィールドを宣言順に返す。
+// unexported な型を更に埋め込んでいる場合は、その型から昇格されるフィールドも含める。
+func (r *structRenderer) buildPromotedFields(current gocode.PackagePath, typ types.Type) []*model.Field {
+	goStruct, ok := derefType(typ).Underlying().(*types.Struct)
+	if !ok {
+		return nil
 	}
-	sort.Slice(orderedFunctions, func(i, j int) bool {
-		return strings.Compare(orderedFunctions[i].Name().String(), orderedFunctions[j].Name().String()) < 0
-	})
 
-	return r.methodRenderer.buildMethods(orderedFunctions)
+	var fields []*model.Field
+	for i := 0; i < goStruct.NumFields(); i++ {
+		f := goStruct.Field(i)
+		if !f.Exported() {
+			if f.Embedded() {
+				fields = append(fields, r.buildPromotedFields(current, f.Type())...)
+			}
+			continue
+		}
+		fields = append(fields, &model.Field{
+			Name:     f.Name(),
+			Type:     r.qualifier.goTypeName(current, f.Type()),
+			Exported: true,
+			Embedded: f.Embedded(),
+		})
+	}
+	return fields
 }
 
-func (r *structRenderer) buildStructFields(st *gocode.Struct) *plantuml.ElementStore {
-	elements := plantuml.NewElementStore()
+func (r *structRenderer) buildStructMethods(st *gocode.Struct) []*model.Method {
+	methods := r.methodRenderer.buildMethods(st.PackageSummary().Path(), st.Methods())
+	if !r.apiSurface {
+		return methods
+	}
+
+	nameSet := make(map[string]struct{})
+	for _, m := range methods {
+		nameSet[m.Name] = struct{}{}
+	}
 	for _, field := range st.Fields() {
-		accessModifier := plantuml.AccessModifierPublic
-		if unicode.IsLower(rune(field.Name().String()[0])) {
-			accessModifier = plantuml.AccessModifierPrivate
+		if !field.Embedded() || field.Exported() {
+			continue
+		}
+		for _, fn := range promotedMethods(field.Type().GoType()) {
+			if _, ok := nameSet[fn.Name()]; ok || !fn.Exported() {
+				continue
+			}
+			nameSet[fn.Name()] = struct{}{}
+			methods = append(methods, buildFunction(r.qualifier, st.PackageSummary().Path(), fn))
+		}
+	}
+	sort.SliceStable(methods, func(i, j int) bool {
+		return strings.Compare(methods[i].Name, methods[j].Name) < 0
+	})
+	return methods
+}
+
+// promotedMethods は埋め込まれた typ から昇格されるメソッドを返す。
+//
+// 埋め込んだ struct のポインタから呼び出せるメソッドとするため、 interface 以外はポインタのメソッドセットとする。
+func promotedMethods(typ types.Type) []*types.Func {
+	typ = derefType(typ)
+	if !types.IsInterface(typ) {
+		typ = types.NewPointer(typ)
+	}
+	methodSet := types.NewMethodSet(typ)
+	methods := make([]*types.Func, 0, methodSet.Len())
+	for i := 0; i < methodSet.Len(); i++ {
+		if fn, ok := methodSet.At(i).Obj().(*types.Func); ok {
+			methods = append(methods, fn)
 		}
+	}
+	return methods
+}
 
-		elements.Add(plantuml.Field(
-			accessModifier,
-			field.Name().String(),
-			field.Type().RelativeFullTypeName().String(),
-		))
+func derefType(typ types.Type) types.Type {
+	if pointer, ok := typ.(*types.Pointer); ok {
+		return pointer.Elem()
+	}
+	return typ
+}
+
+// fieldMultiplicity はフィールドの型 fieldType が target の型の値をいくつ保持するかを多重度として返す。
+//
+// スライス、配列、マップ、チャネルを経由する場合は *、ポインタのみを経由する場合は 0..1、それ以外は 1 とする。
+// 関数の引数や他の型の型引数などにのみ現れる場合は、多重度を表せないため空文字列を返す。
+func fieldMultiplicity(fieldType types.Type, target types.Type) string {
+	named, ok := derefType(target).(*types.Named)
+	if !ok {
+		return ""
+	}
+	multiplicity, _ := findMultiplicity(fieldType, typeKey(named.Origin()))
+	return multiplicity
+}
+
+func findMultiplicity(typ types.Type, key string) (string, bool) {
+	switch t := typ.(type) {
+	case *types.Named:
+		if typeKey(t.Origin()) == key {
+			return "1", true
+		}
+		return "", false
+	case *types.Pointer:
+		multiplicity, ok := findMultiplicity(t.Elem(), key)
+		if multiplicity == "1" {
+			multiplicity = "0..1"
+		}
+		return multiplicity, ok
+	case *types.Slice:
+		return findMany(key, t.Elem())
+	case *types.Array:
+		return findMany(key, t.Elem())
+	case *types.Chan:
+		return findMany(key, t.Elem())
+	case *types.Map:
+		return findMany(key, t.Key(), t.Elem())
+	default:
+		return "", false
+	}
+}
+
+// findMany は要素の型 elems のいずれかに key の型が現れる場合に * を返す。
+func findMany(key string, elems ...types.Type) (string, bool) {
+	for _, elem := range elems {
+		if multiplicity, ok := findMultiplicity(elem, key); ok && multiplicity != "" {
+			return "*", true
+		}
 	}
-	return elements
+	return "", false
 }
@@ -1,11 +1,11 @@
 package renderer
 
 import (
-	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/keisuke-m123/goanalyzer/gocode"
-	"github.com/keisuke-m123/godiagramgen/plantuml"
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
 )
 
 type (
@@ -20,51 +20,42 @@ func newAliasRender(relations *gocode.Relations) *aliasRenderer {
 	}
 }
 
-func (ar *aliasRenderer) buildTypeAliases() *plantuml.ElementStore {
-	orderedAliases := ar.relations.TypeAliases().AliasAll()
-	sort.Slice(orderedAliases, func(i, j int) bool {
-		ai := orderedAliases[i]
-		aj := orderedAliases[j]
-		return fmt.Sprintf("%s %s %s", ai.Name(), ai.PackageSummary().Name(), ai.Type().TypeName()) <
-			fmt.Sprintf("%s %s %s", aj.Name(), aj.PackageSummary().Name(), aj.Type().TypeName())
-	})
-
-	elements := plantuml.NewElementStore()
-	color, _ := plantuml.ParseHexColor("#EDDC44")
-	for _, alias := range orderedAliases {
-		pkgName := alias.PackageSummary().Name().String()
-		aliasName := alias.Name().String()
-		var stereotype string
+func (ar *aliasRenderer) buildInPkg(pkgDetail *gocode.PackageDetail) []*model.Node {
+	var nodes []*model.Node
+	for _, alias := range ar.sortedAliases(pkgDetail) {
+		var underlying string
 		if alias.Type().Builtin() {
-			stereotype = fmt.Sprintf("alias of __%s__", alias.Type().TypeName().String())
+			underlying = alias.Type().TypeName().String()
 		}
-		elements.Add(
-			plantuml.Namespace(
-				pkgName,
-				plantuml.ClassWithOption(
-					aliasName,
-					plantuml.ClassOptions{
-						Stereotype: plantuml.Stereotype(stereotype),
-						Spot: plantuml.Spot{
-							Name:  'T',
-							Color: color,
-						},
-					},
-				),
-			),
-		)
+		nodes = append(nodes, &model.Node{
+			Kind:       model.NodeKindTypeAlias,
+			Package:    newPackageRef(alias.PackageSummary()),
+			Name:       alias.Name().String(),
+			Underlying: underlying,
+		})
+	}
+	return nodes
+}
+
+func (ar *aliasRenderer) buildRelations(pkgDetail *gocode.PackageDetail) []*model.Edge {
+	var edges []*model.Edge
+	for _, alias := range ar.sortedAliases(pkgDetail) {
 		if alias.Type().Builtin() {
 			continue
 		}
-
-		rtFrom := plantuml.NewRelationTargetWithNamespace(pkgName, aliasName)
-		rtTo := plantuml.NewRelationTargetWithNamespace(
-			alias.Type().PackageSummary().Name().String(),
-			alias.Type().TypeName().String(),
-		)
-
-		elements.Add(plantuml.Relation(rtFrom, rtTo, plantuml.RelationTypeAlias))
+		edges = append(edges, &model.Edge{
+			Kind: model.EdgeKindAlias,
+			From: newNodeRef(alias.PackageSummary(), alias.Name().String()),
+			To:   newNodeRef(alias.Type().PackageSummary(), alias.Type().TypeName().String()),
+		})
 	}
+	return edges
+}
 
-	return elements
+func (ar *aliasRenderer) sortedAliases(pkgDetail *gocode.PackageDetail) []*gocode.TypeAlias {
+	aliases := append([]*gocode.TypeAlias{}, pkgDetail.TypeAliases()...)
+	sort.Slice(aliases, func(i, j int) bool {
+		return strings.Compare(aliases[i].Name().String(), aliases[j].Name().String()) < 0
+	})
+	return aliases
 }
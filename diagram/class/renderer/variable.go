@@ -0,0 +1,45 @@
+package renderer
+
+import (
+	"go/token"
+
+	"github.com/keisuke-m123/goanalyzer/gocode"
+	"github.com/keisuke-m123/godiagramgen/diagram/class/declaration"
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+)
+
+type (
+	// variableRenderer はパッケージレベルの変数を、パッケージ毎に一つのノードにまとめて描画する。
+	variableRenderer struct {
+		declarations *declaration.Declarations
+		qualifier    *packageQualifier
+	}
+)
+
+func newVariableRenderer(declarations *declaration.Declarations, qualifier *packageQualifier) *variableRenderer {
+	return &variableRenderer{
+		declarations: declarations,
+		qualifier:    qualifier,
+	}
+}
+
+func (r *variableRenderer) buildInPkg(pkgSummary *gocode.PackageSummary) []*model.Node {
+	variables := r.declarations.Variables(pkgSummary.Path())
+	if len(variables) == 0 {
+		return nil
+	}
+
+	node := &model.Node{
+		Kind:    model.NodeKindPackageVariables,
+		Package: newPackageRef(pkgSummary),
+		Name:    model.PackageVariablesNodeName,
+	}
+	for _, v := range variables {
+		node.Fields = append(node.Fields, &model.Field{
+			Name:     v.Name(),
+			Type:     r.qualifier.goTypeName(pkgSummary.Path(), v.Type()),
+			Exported: token.IsExported(v.Name()),
+		})
+	}
+	return []*model.Node{node}
+}
@@ -0,0 +1,269 @@
+// Package diff は二つの図のモデルを比較し、追加、削除、変更されたパッケージ、型、メンバー、関連を表す図を生成する。
+//
+// パッケージと型はインポートパスと名前で、メンバーは名前で、関連は種類と両端とラベルで同一とみなす。
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+)
+
+type (
+	edgeKey struct {
+		kind  model.EdgeKind
+		from  model.NodeRef
+		to    model.NodeRef
+		label string
+		// fromMultiplicity と toMultiplicity はフィールドの型の変更による多重度の変更を、関連の変更として扱うために含める。
+		fromMultiplicity string
+		toMultiplicity   string
+	}
+)
+
+// Compare は base から head への変更を ChangeStatus として設定した図を返す。
+//
+// 図の内容は head を基本とし、 base にのみ存在するパッケージ、型、メンバー、関連を削除されたものとして加える。
+// base と head は変更しない。
+func Compare(base, head *model.Diagram) *model.Diagram {
+	d := *head
+	d.Packages = nil
+
+	basePackages := make(map[model.PackageRef]*model.Package)
+	for _, pkg := range base.Packages {
+		basePackages[pkg.PackageRef] = pkg
+	}
+	headPackageSet := make(map[model.PackageRef]struct{})
+	for _, pkg := range head.Packages {
+		headPackageSet[pkg.PackageRef] = struct{}{}
+		if basePkg, ok := basePackages[pkg.PackageRef]; ok {
+			d.Packages = append(d.Packages, comparePackage(basePkg, pkg))
+		} else {
+			d.Packages = append(d.Packages, withPackageStatus(pkg, model.ChangeStatusAdded))
+		}
+	}
+	for _, pkg := range base.Packages {
+		if _, ok := headPackageSet[pkg.PackageRef]; !ok {
+			d.Packages = append(d.Packages, withPackageStatus(pkg, model.ChangeStatusRemoved))
+		}
+	}
+
+	sort.SliceStable(d.Packages, func(i, j int) bool {
+		pi, pj := d.Packages[i], d.Packages[j]
+		if pi.Name != pj.Name {
+			return strings.Compare(pi.Name, pj.Name) < 0
+		}
+		return strings.Compare(pi.Path, pj.Path) < 0
+	})
+	return &d
+}
+
+// withPackageStatus はパッケージと、パッケージに所属する全てのノードと関連に status を設定したコピーを返す。
+func withPackageStatus(pkg *model.Package, status model.ChangeStatus) *model.Package {
+	p := &model.Package{PackageRef: pkg.PackageRef, Status: status}
+	for _, node := range pkg.Nodes {
+		n := *node
+		n.Status = status
+		p.Nodes = append(p.Nodes, &n)
+	}
+	for _, edge := range pkg.Edges {
+		p.Edges = append(p.Edges, withEdgeStatus(edge, status))
+	}
+	return p
+}
+
+func withEdgeStatus(edge *model.Edge, status model.ChangeStatus) *model.Edge {
+	e := *edge
+	e.Status = status
+	return &e
+}
+
+func comparePackage(base, head *model.Package) *model.Package {
+	p := &model.Package{PackageRef: head.PackageRef}
+
+	baseNodes := make(map[string]*model.Node)
+	for _, node := range base.Nodes {
+		baseNodes[node.Name] = node
+	}
+	headNodeSet := make(map[string]struct{})
+	for _, node := range head.Nodes {
+		headNodeSet[node.Name] = struct{}{}
+		if baseNode, ok := baseNodes[node.Name]; ok {
+			p.Nodes = append(p.Nodes, compareNode(baseNode, node))
+		} else {
+			n := *node
+			n.Status = model.ChangeStatusAdded
+			p.Nodes = append(p.Nodes, &n)
+		}
+	}
+	for _, node := range base.Nodes {
+		if _, ok := headNodeSet[node.Name]; !ok {
+			n := *node
+			n.Status = model.ChangeStatusRemoved
+			p.Nodes = append(p.Nodes, &n)
+		}
+	}
+
+	baseEdgeSet := make(map[edgeKey]struct{})
+	for _, edge := range base.Edges {
+		baseEdgeSet[newEdgeKey(edge)] = struct{}{}
+	}
+	headEdgeSet := make(map[edgeKey]struct{})
+	for _, edge := range head.Edges {
+		headEdgeSet[newEdgeKey(edge)] = struct{}{}
+		if _, ok := baseEdgeSet[newEdgeKey(edge)]; ok {
+			p.Edges = append(p.Edges, edge)
+		} else {
+			p.Edges = append(p.Edges, withEdgeStatus(edge, model.ChangeStatusAdded))
+		}
+	}
+	for _, edge := range base.Edges {
+		if _, ok := headEdgeSet[newEdgeKey(edge)]; !ok {
+			p.Edges = append(p.Edges, withEdgeStatus(edge, model.ChangeStatusRemoved))
+		}
+	}
+	return p
+}
+
+func newEdgeKey(edge *model.Edge) edgeKey {
+	return edgeKey{
+		kind:             edge.Kind,
+		from:             edge.From,
+		to:               edge.To,
+		label:            edge.Label,
+		fromMultiplicity: edge.FromMultiplicity,
+		toMultiplicity:   edge.ToMultiplicity,
+	}
+}
+
+// compareNode はメンバーの変更を設定したノードを返す。
+// 種類や型パラメータなどの宣言、またはメンバーのいずれかが変更されている場合に、ノードを変更されたものとする。
+func compareNode(base, head *model.Node) *model.Node {
+	n := *head
+	n.Constants = compareConstants(base.Constants, head.Constants)
+	n.Fields = compareFields(base.Fields, head.Fields)
+	n.Methods = compareMethods(base.Methods, head.Methods)
+
+	if declaration(base) != declaration(head) || hasMemberChanges(&n) {
+		n.Status = model.ChangeStatusChanged
+	}
+	return &n
+}
+
+func declaration(node *model.Node) string {
+	return fmt.Sprintf("%d %s %s %s", node.Kind, node.DisplayName(), node.Underlying, strings.Join(node.TypeSet, "|"))
+}
+
+func hasMemberChanges(node *model.Node) bool {
+	for _, c := range node.Constants {
+		if c.Status != model.ChangeStatusNone {
+			return true
+		}
+	}
+	for _, f := range node.Fields {
+		if f.Status != model.ChangeStatusNone {
+			return true
+		}
+	}
+	for _, m := range node.Methods {
+		if m.Status != model.ChangeStatusNone {
+			return true
+		}
+	}
+	return false
+}
+
+func compareConstants(base, head []*model.Constant) []*model.Constant {
+	baseConstants := make(map[string]*model.Constant)
+	for _, c := range base {
+		baseConstants[c.Name] = c
+	}
+	headNameSet := make(map[string]struct{})
+	var constants []*model.Constant
+	for _, c := range head {
+		headNameSet[c.Name] = struct{}{}
+		compared := *c
+		if baseConstant, ok := baseConstants[c.Name]; !ok {
+			compared.Status = model.ChangeStatusAdded
+		} else if baseConstant.Value != c.Value {
+			compared.Status = model.ChangeStatusChanged
+		}
+		constants = append(constants, &compared)
+	}
+	for _, c := range base {
+		if _, ok := headNameSet[c.Name]; !ok {
+			removed := *c
+			removed.Status = model.ChangeStatusRemoved
+			constants = append(constants, &removed)
+		}
+	}
+	return constants
+}
+
+func compareFields(base, head []*model.Field) []*model.Field {
+	baseFields := make(map[string]*model.Field)
+	for _, f := range base {
+		baseFields[f.Name] = f
+	}
+	headNameSet := make(map[string]struct{})
+	var fields []*model.Field
+	for _, f := range head {
+		headNameSet[f.Name] = struct{}{}
+		compared := *f
+		if baseField, ok := baseFields[f.Name]; !ok {
+			compared.Status = model.ChangeStatusAdded
+		} else if baseField.Type != f.Type || baseField.Embedded != f.Embedded {
+			compared.Status = model.ChangeStatusChanged
+		}
+		fields = append(fields, &compared)
+	}
+	for _, f := range base {
+		if _, ok := headNameSet[f.Name]; !ok {
+			removed := *f
+			removed.Status = model.ChangeStatusRemoved
+			fields = append(fields, &removed)
+		}
+	}
+	return fields
+}
+
+func compareMethods(base, head []*model.Method) []*model.Method {
+	baseMethods := make(map[string]*model.Method)
+	for _, m := range base {
+		baseMethods[m.Name] = m
+	}
+	headNameSet := make(map[string]struct{})
+	var methods []*model.Method
+	for _, m := range head {
+		headNameSet[m.Name] = struct{}{}
+		compared := *m
+		if baseMethod, ok := baseMethods[m.Name]; !ok {
+			compared.Status = model.ChangeStatusAdded
+		} else if signature(baseMethod) != signature(m) {
+			compared.Status = model.ChangeStatusChanged
+		}
+		methods = append(methods, &compared)
+	}
+	for _, m := range base {
+		if _, ok := headNameSet[m.Name]; !ok {
+			removed := *m
+			removed.Status = model.ChangeStatusRemoved
+			methods = append(methods, &removed)
+		}
+	}
+	return methods
+}
+
+// signature は型パラメータと引数と戻り値の型を比較するための文字列を返す。引数と戻り値の名前の変更は無視する。
+func signature(m *model.Method) string {
+	var params, results []string
+	for _, p := range m.Params {
+		params = append(params, p.Type)
+	}
+	for _, r := range m.Results {
+		results = append(results, r.Type)
+	}
+	return fmt.Sprintf("%s(%s) (%s)", m.DisplayName(), strings.Join(params, ", "), strings.Join(results, ", "))
+}
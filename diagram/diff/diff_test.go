@@ -0,0 +1,80 @@
+package diff
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/keisuke-m123/godiagramgen/diagram/class"
+	"github.com/keisuke-m123/godiagramgen/diagram/class/renderer"
+	"github.com/keisuke-m123/godiagramgen/diagram/emitter"
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+	"github.com/keisuke-m123/godiagramgen/testutil"
+)
+
+const (
+	basePackagePath = "github.com/keisuke-m123/godiagramgen/testingsupport/diff/base"
+	headPackagePath = "github.com/keisuke-m123/godiagramgen/testingsupport/diff/head"
+)
+
+func TestCompare(t *testing.T) {
+	renderingOptions := &renderer.RenderingOptions{RenderConstants: true}
+	base := loadModel(t, "../../testingsupport/diff/base", renderingOptions)
+	head := loadModel(t, "../../testingsupport/diff/head", renderingOptions)
+	// 同じパッケージの二つのバージョンとして比較するため、 base のインポートパスを head に揃える。
+	movePackage(base, basePackagePath, headPackagePath)
+
+	d := Compare(base, head)
+	if !d.HasChanges() {
+		t.Fatalf("want changes")
+	}
+
+	wantFilePath := "../../testingsupport/diff.puml"
+	fileBytes, err := ioutil.ReadFile(wantFilePath)
+	if err != nil {
+		t.Fatalf("failed open want file %s: %s", wantFilePath, err)
+	}
+
+	got := emitter.NewPlantUMLClassEmitter().Emit(d)
+	want := string(fileBytes)
+
+	if got != want {
+		t.Errorf(
+			"failed render: want %s\n\ngot %s\n\ndiff: %s",
+			want,
+			got,
+			testutil.Diff(t, want, got),
+		)
+	}
+}
+
+func TestCompare_NoChanges(t *testing.T) {
+	head := loadModel(t, "../../testingsupport/diff/head", &renderer.RenderingOptions{RenderConstants: true})
+	if d := Compare(head, head); d.HasChanges() {
+		t.Errorf("want no changes: %s", emitter.NewPlantUMLClassEmitter().Emit(d))
+	}
+}
+
+func loadModel(t *testing.T, dir string, renderingOptions *renderer.RenderingOptions) *model.Diagram {
+	t.Helper()
+	d, err := class.NewDiagram([]string{dir}, nil, false, renderingOptions)
+	if err != nil {
+		t.Fatalf("failed NewDiagram: %s", err)
+	}
+	return d.Model()
+}
+
+// movePackage は d の from のパッケージと、そのパッケージへの参照を to のパッケージに置き換える。
+func movePackage(d *model.Diagram, from, to string) {
+	move := func(ref *model.PackageRef) {
+		if ref.Path == from {
+			ref.Path = to
+		}
+	}
+	for _, p := range d.Packages {
+		move(&p.PackageRef)
+		for _, edge := range p.Edges {
+			move(&edge.From.Package)
+			move(&edge.To.Package)
+		}
+	}
+}
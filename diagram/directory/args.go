@@ -0,0 +1,50 @@
+package directory
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FromArgs はコマンドの引数 args のディレクトリが存在することを検証し、絶対パスに変換する。
+func FromArgs(args []string) ([]string, error) {
+	if len(args) < 1 {
+		return nil, errors.New("DIR missing")
+	}
+	var dirs []string
+	for _, dir := range args {
+		fi, err := os.Stat(dir)
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("could not find directory %s", dir)
+		}
+		if !fi.Mode().IsDir() {
+			return nil, fmt.Errorf("%s is not a directory", dir)
+		}
+		dirAbs, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, fmt.Errorf("could not find directory %s", dir)
+		}
+		dirs = append(dirs, dirAbs)
+	}
+	return dirs, nil
+}
+
+// IgnoredFromList はカンマ区切りの除外するディレクトリ list を絶対パスに変換する。
+func IgnoredFromList(list string) ([]string, error) {
+	var result []string
+	list = strings.TrimSpace(list)
+	if list == "" {
+		return result, nil
+	}
+	split := strings.Split(list, ",")
+	for _, dir := range split {
+		dirAbs, err := filepath.Abs(strings.TrimSpace(dir))
+		if err != nil {
+			return nil, fmt.Errorf("could not find directory %s", dir)
+		}
+		result = append(result, dirAbs)
+	}
+	return result, nil
+}
@@ -0,0 +1,54 @@
+// Package directory は解析するディレクトリを扱う。
+//
+// コマンドの引数からディレクトリを検証して絶対パスに変換し(FromArgs, IgnoredFromList)、
+// gocode 以外の方法でパッケージを読み込む場合も gocode.LoadRelations と同じ規則でディレクトリを列挙する(Collect)。
+package directory
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/keisuke-m123/goanalyzer/gocode"
+	"github.com/spf13/afero"
+)
+
+// Collect は options に従って解析するディレクトリを列挙する。
+//
+// options.Recursive の場合は options.Directories 配下の全てのディレクトリを辿り、
+// . で始まるディレクトリ、 vendor ディレクトリ、 options.IgnoredDirectories のディレクトリとその配下は除く。
+// それ以外の場合は options.Directories をそのまま返す。
+func Collect(options *gocode.LoadOptions) ([]string, error) {
+	if !options.Recursive {
+		return options.Directories, nil
+	}
+
+	ignoreDirectoryMap := map[string]struct{}{}
+	for _, dir := range options.IgnoredDirectories {
+		ignoreDirectoryMap[dir] = struct{}{}
+	}
+
+	var dirs []string
+	for _, directoryPath := range options.Directories {
+		err := afero.Walk(options.FileSystem, directoryPath, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() {
+				return nil
+			}
+			if strings.HasPrefix(info.Name(), ".") || info.Name() == "vendor" {
+				return filepath.SkipDir
+			}
+			if _, ok := ignoreDirectoryMap[path]; ok {
+				return filepath.SkipDir
+			}
+			dirs = append(dirs, path)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dirs, nil
+}
@@ -0,0 +1,62 @@
+package directory
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/keisuke-m123/goanalyzer/gocode"
+	"github.com/spf13/afero"
+)
+
+func TestCollect(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	for _, dir := range []string{"root/a/b", "root/.git/objects", "root/vendor/lib", "root/ignored/c", "root/d"} {
+		if err := fs.MkdirAll(filepath.FromSlash(dir), 0755); err != nil {
+			t.Fatalf("failed to create %s: %s", dir, err)
+		}
+	}
+	if err := afero.WriteFile(fs, filepath.FromSlash("root/a/a.go"), []byte("package a\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %s", err)
+	}
+
+	tests := []struct {
+		name    string
+		options *gocode.LoadOptions
+		want    []string
+	}{
+		{
+			name: "Recursive",
+			options: &gocode.LoadOptions{
+				FileSystem:         fs,
+				Directories:        []string{"root"},
+				IgnoredDirectories: []string{filepath.FromSlash("root/ignored")},
+				Recursive:          true,
+			},
+			want: []string{"root", "root/a", "root/a/b", "root/d"},
+		},
+		{
+			name: "NotRecursive",
+			options: &gocode.LoadOptions{
+				FileSystem:  fs,
+				Directories: []string{"root/a", "root/vendor/lib"},
+			},
+			want: []string{"root/a", "root/vendor/lib"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dirs, err := Collect(test.options)
+			if err != nil {
+				t.Fatalf("Collect() error = %v", err)
+			}
+			for i := range test.want {
+				test.want[i] = filepath.FromSlash(test.want[i])
+			}
+			if strings.Join(dirs, ",") != strings.Join(test.want, ",") {
+				t.Errorf("Collect() = %v, want %v", dirs, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package emitter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+	"github.com/keisuke-m123/godiagramgen/plantuml"
+)
+
+type (
+	// changeColor は差分の図で変更の種類を表す色。
+	// background は型やパッケージの背景色、 foreground はメンバーの文字色と関連の線の色とする。
+	changeColor struct {
+		label      string
+		background string
+		foreground string
+	}
+)
+
+var changeColors = map[model.ChangeStatus]changeColor{
+	model.ChangeStatusAdded:   {label: "added", background: "#C8F7C5", foreground: "#228B22"},
+	model.ChangeStatusRemoved: {label: "removed", background: "#F7C5C5", foreground: "#B22222"},
+	model.ChangeStatusChanged: {label: "changed", background: "#FFF3B0", foreground: "#B8860B"},
+}
+
+// changeBackgroundColor は status の背景色を返す。変更がない場合は nil を返す。
+func changeBackgroundColor(status model.ChangeStatus) *plantuml.Color {
+	c, ok := changeColors[status]
+	if !ok {
+		return nil
+	}
+	color, _ := plantuml.ParseHexColor(c.background)
+	return color
+}
+
+// changeForegroundColor は status の文字色と線の色を返す。変更がない場合は nil を返す。
+func changeForegroundColor(status model.ChangeStatus) *plantuml.Color {
+	c, ok := changeColors[status]
+	if !ok {
+		return nil
+	}
+	color, _ := plantuml.ParseHexColor(c.foreground)
+	return color
+}
+
+// plantUMLLegend は notes と、 d が変更を含む場合は色の凡例を合わせた凡例を返す。
+// PlantUML の図には凡例を一つしか置けないため、一つにまとめる。
+func plantUMLLegend(d *model.Diagram) string {
+	var lines []string
+	if note := strings.TrimSpace(d.Notes); note != "" {
+		lines = append(lines, d.Notes)
+	}
+	if d.HasChanges() {
+		lines = append(lines, "<b><u>Changes</u></b>")
+		for _, status := range []model.ChangeStatus{model.ChangeStatusAdded, model.ChangeStatusRemoved, model.ChangeStatusChanged} {
+			c := changeColors[status]
+			lines = append(lines, fmt.Sprintf("<back:%s><color:%s>%s</color></back>", c.background, c.foreground, c.label))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,128 @@
+package emitter
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+	"github.com/keisuke-m123/godiagramgen/graphviz"
+)
+
+type (
+	// DOTPackageEmitter はパッケージ図を Graphviz の DOT 形式に変換する。
+	//
+	// パッケージはパスのセグメント毎にクラスタとしてまとめて出力する。
+	DOTPackageEmitter struct{}
+
+	// packageTree はパッケージパスを "/" 区切りのセグメント毎に木構造として保持する。
+	packageTree struct {
+		segment   string
+		path      string
+		isPackage bool
+		fillColor string
+		children  map[string]*packageTree
+	}
+)
+
+func NewDOTPackageEmitter() *DOTPackageEmitter {
+	return &DOTPackageEmitter{}
+}
+
+func (e *DOTPackageEmitter) Emit(d *model.Diagram) string {
+	origins := packageOrigins(d)
+	fillColors := make(map[string]string)
+	for _, pkg := range d.Packages {
+		fillColors[pkg.Path] = packageFillColor(pkg)
+	}
+	tree := newPackageTree("", "")
+	for _, pkg := range d.Packages {
+		tree.add(pkg.Path, fillColors[pkg.Path])
+		for _, edge := range pkg.Edges {
+			tree.add(edge.To.Package.Path, fillColors[edge.To.Package.Path])
+		}
+	}
+
+	elements := graphviz.NewElementStore()
+	elements.Add(graphviz.NodeDefaults(graphviz.Attributes{"shape": "box"}))
+	for _, child := range tree.sortedChildren() {
+		elements.Add(child.buildElements("")...)
+	}
+	for _, edge := range d.Edges() {
+		attributes := graphviz.Attributes{}
+		// 標準ライブラリと他のモジュールの import は破線とする。
+		if origins[edge.To.Package.Path] != model.PackageOriginModule {
+			attributes["style"] = "dashed"
+		}
+		if edge.Violation {
+			attributes["color"] = violationColor
+		}
+		elements.Add(graphviz.Edge(edge.From.Package.Path, edge.To.Package.Path, attributes))
+	}
+	return graphviz.Digraph("packages", elements.AsSlice()...).String()
+}
+
+func newPackageTree(segment, path string) *packageTree {
+	return &packageTree{
+		segment:  segment,
+		path:     path,
+		children: make(map[string]*packageTree),
+	}
+}
+
+func (t *packageTree) add(pkgPath string, fillColor string) {
+	current := t
+	for _, segment := range strings.Split(pkgPath, "/") {
+		child, ok := current.children[segment]
+		if !ok {
+			child = newPackageTree(segment, path.Join(current.path, segment))
+			current.children[segment] = child
+		}
+		current = child
+	}
+	current.isPackage = true
+	current.fillColor = fillColor
+}
+
+// nodeAttributes は label に加えて、メトリクスやパッケージの提供元を表す色で塗りつぶす属性を返す。
+func (t *packageTree) nodeAttributes(label string) graphviz.Attributes {
+	attributes := graphviz.Attributes{"label": label}
+	if t.fillColor != "" {
+		attributes["style"] = "filled"
+		attributes["fillcolor"] = t.fillColor
+	}
+	return attributes
+}
+
+func (t *packageTree) sortedChildren() []*packageTree {
+	var children []*packageTree
+	for _, child := range t.children {
+		children = append(children, child)
+	}
+	sort.Slice(children, func(i, j int) bool {
+		return strings.Compare(children[i].segment, children[j].segment) < 0
+	})
+	return children
+}
+
+// buildElements は木構造をクラスタとノードに変換する。
+// パッケージではない中間セグメントが一つの子しか持たない場合、ネストが深くなりすぎないようにクラスタをまとめる。
+func (t *packageTree) buildElements(labelPrefix string) []graphviz.Element {
+	label := path.Join(labelPrefix, t.segment)
+	children := t.sortedChildren()
+	if len(children) == 0 {
+		return []graphviz.Element{graphviz.Node(t.path, t.nodeAttributes(label))}
+	}
+	if !t.isPackage && len(children) == 1 {
+		return children[0].buildElements(label)
+	}
+
+	elements := graphviz.NewElementStore()
+	if t.isPackage {
+		elements.Add(graphviz.Node(t.path, t.nodeAttributes(t.segment)))
+	}
+	for _, child := range children {
+		elements.Add(child.buildElements("")...)
+	}
+	return []graphviz.Element{graphviz.Cluster(t.path, label, elements.AsSlice()...)}
+}
@@ -0,0 +1,9 @@
+// Package emitter は model.Diagram を各出力形式の文字列に変換する。
+package emitter
+
+import "github.com/keisuke-m123/godiagramgen/diagram/model"
+
+// Emitter は model.Diagram を特定の出力形式に変換する。
+type Emitter interface {
+	Emit(d *model.Diagram) string
+}
@@ -0,0 +1,26 @@
+package emitter
+
+import (
+	"encoding/json"
+
+	"github.com/keisuke-m123/godiagramgen/diagram/export"
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+)
+
+type (
+	// JSONEmitter は図のモデルを export.Document の JSON 形式に変換する。
+	JSONEmitter struct{}
+)
+
+func NewJSONEmitter() *JSONEmitter {
+	return &JSONEmitter{}
+}
+
+func (e *JSONEmitter) Emit(d *model.Diagram) string {
+	b, err := json.MarshalIndent(export.NewDocument(d), "", "  ")
+	if err != nil {
+		// export.Document は JSON に変換できない値を保持しないため、ここに到達することはない。
+		panic(err)
+	}
+	return string(b) + "\n"
+}
@@ -0,0 +1,158 @@
+package emitter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+	"github.com/keisuke-m123/godiagramgen/mermaid"
+)
+
+type (
+	// MermaidClassEmitter はクラス図を Mermaid の classDiagram 形式に変換する。
+	//
+	// Mermaid の namespace は同名のブロックを複数回宣言できないため、パッケージ毎に全ての型を一つの namespace にまとめ、
+	// 関連は全ての namespace の後にまとめて出力する。
+	MermaidClassEmitter struct{}
+)
+
+func NewMermaidClassEmitter() *MermaidClassEmitter {
+	return &MermaidClassEmitter{}
+}
+
+func (e *MermaidClassEmitter) Emit(d *model.Diagram) string {
+	elements := mermaid.NewElementStore()
+	if note := strings.TrimSpace(d.Notes); note != "" {
+		elements.Add(mermaid.Note(d.Notes))
+	}
+
+	relations := mermaid.NewElementStore()
+	uniqueRelationSet := make(map[string]struct{})
+	ns := newPackageNamespaces(d, mermaid.NamespaceID, escapedMermaidNamespaceID, mermaidClassID)
+	for _, pkg := range d.Packages {
+		classes := mermaid.NewElementStore()
+		for _, node := range pkg.Nodes {
+			classes.Add(e.buildNode(ns, node))
+		}
+		elements.Add(mermaid.Namespace(ns.name(pkg.PackageRef), classes.AsSlice()...))
+
+		for _, edge := range pkg.Edges {
+			key := fmt.Sprintf("%d %s %s %s", edge.Kind, e.classID(ns, edge.From), e.classID(ns, edge.To), edge.Label)
+			if _, ok := uniqueRelationSet[key]; ok {
+				continue
+			}
+			uniqueRelationSet[key] = struct{}{}
+			relations.Add(e.buildEdge(ns, edge))
+		}
+	}
+	elements.Add(relations.AsSlice()...)
+
+	return mermaid.ClassDiagram(mermaid.DiagramOptions{Title: d.Title}, elements.AsSlice()...).String()
+}
+
+func (e *MermaidClassEmitter) buildNode(ns packageNamespaces, node *model.Node) mermaid.Element {
+	return mermaid.ClassWithOption(
+		e.classID(ns, node.Ref()),
+		mermaid.ClassOptions{
+			Label:      node.DisplayName(),
+			Annotation: mermaid.Annotation(e.annotation(node)),
+		},
+		e.buildMembers(node)...,
+	)
+}
+
+func (e *MermaidClassEmitter) annotation(node *model.Node) string {
+	switch node.Kind {
+	case model.NodeKindStruct:
+		return "struct"
+	case model.NodeKindInterface:
+		return "interface"
+	case model.NodeKindConstraint:
+		return fmt.Sprintf("constraint %s", strings.Join(node.TypeSet, "; "))
+	case model.NodeKindDefinedType:
+		if node.Underlying != "" {
+			return fmt.Sprintf("type of %s", node.Underlying)
+		}
+		return "defined type"
+	case model.NodeKindTypeAlias:
+		if node.Underlying != "" {
+			return fmt.Sprintf("alias of %s", node.Underlying)
+		}
+		return "alias"
+	case model.NodeKindPackageFunctions:
+		return "functions"
+	case model.NodeKindPackageVariables:
+		return "variables"
+	default:
+		switch underlyingTypeSpotName(node.DisplayName()) {
+		case 's':
+			return "slice"
+		case 'm':
+			return "map"
+		case 'f':
+			return "func"
+		default:
+			return "type"
+		}
+	}
+}
+
+func (e *MermaidClassEmitter) buildMembers(node *model.Node) []mermaid.Element {
+	elements := mermaid.NewElementStore()
+	for _, c := range node.Constants {
+		elements.Add(mermaid.Constant(c.Name, c.Value))
+	}
+	for _, f := range node.Fields {
+		elements.Add(mermaid.Field(e.accessModifier(f.Exported), f.Name, f.Type))
+	}
+	for _, m := range node.Methods {
+		params := make(mermaid.Params, 0)
+		for _, p := range m.Params {
+			params = append(params, mermaid.Param{Name: p.Name, Type: p.Type})
+		}
+		returnValues := make(mermaid.ReturnValues, 0)
+		for _, r := range m.Results {
+			returnValues = append(returnValues, mermaid.ReturnValue{Type: r.Type})
+		}
+		elements.Add(mermaid.Method(e.accessModifier(m.Exported), m.DisplayName(), params, returnValues))
+	}
+	return elements.AsSlice()
+}
+
+func (e *MermaidClassEmitter) accessModifier(exported bool) mermaid.AccessModifier {
+	if exported {
+		return mermaid.AccessModifierPublic
+	}
+	return mermaid.AccessModifierPrivate
+}
+
+func (e *MermaidClassEmitter) buildEdge(ns packageNamespaces, edge *model.Edge) mermaid.Element {
+	from := e.classID(ns, edge.From)
+	to := e.classID(ns, edge.To)
+	options := mermaid.RelationOptions{
+		Label:            edge.Label,
+		FromMultiplicity: edge.FromMultiplicity,
+		ToMultiplicity:   edge.ToMultiplicity,
+	}
+	switch edge.Kind {
+	case model.EdgeKindExtension:
+		return mermaid.RelationWithOption(from, to, mermaid.RelationTypeExtension, options)
+	case model.EdgeKindComposition:
+		return mermaid.RelationWithOption(from, to, mermaid.RelationTypeComposition, options)
+	case model.EdgeKindAggregation:
+		// from と to を入れ替えるため、多重度も入れ替える。
+		options.FromMultiplicity, options.ToMultiplicity = edge.ToMultiplicity, edge.FromMultiplicity
+		return mermaid.RelationWithOption(to, from, mermaid.RelationTypeAggregation, options)
+	case model.EdgeKindAlias:
+		return mermaid.RelationWithOption(from, to, mermaid.RelationTypeAlias, options)
+	case model.EdgeKindCreation, model.EdgeKindDependency:
+		return mermaid.RelationWithOption(from, to, mermaid.RelationTypeDependency, options)
+	default:
+		return mermaid.RelationWithOption(from, to, mermaid.RelationTypeAssociation, options)
+	}
+}
+
+// classID はポインタを表す * を取り除いたうえでクラスIDを生成する。
+func (e *MermaidClassEmitter) classID(ns packageNamespaces, ref model.NodeRef) string {
+	return mermaidClassID(ns.name(ref.Package), ref.Name)
+}
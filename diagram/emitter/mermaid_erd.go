@@ -0,0 +1,43 @@
+package emitter
+
+import (
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+	"github.com/keisuke-m123/godiagramgen/mermaid"
+)
+
+type (
+	// MermaidERDEmitter はエンティティ関連図を Mermaid の erDiagram 形式に変換する。
+	//
+	// erDiagram はエンティティの表示名を持たないため、エンティティの ID を名前とする。
+	MermaidERDEmitter struct{}
+)
+
+func NewMermaidERDEmitter() *MermaidERDEmitter {
+	return &MermaidERDEmitter{}
+}
+
+func (e *MermaidERDEmitter) Emit(s *model.Schema) string {
+	elements := mermaid.NewElementStore()
+	for _, entity := range s.Entities {
+		var attributes []mermaid.Element
+		for _, c := range entity.Columns {
+			var keys []string
+			if c.PrimaryKey {
+				keys = append(keys, "PK")
+			}
+			if c.ForeignKey {
+				keys = append(keys, "FK")
+			}
+			attributes = append(attributes, mermaid.Attribute(c.Type, c.Name, keys...))
+		}
+		elements.Add(mermaid.Entity(entity.ID, attributes...))
+	}
+	for _, r := range s.Relationships {
+		toCardinality := mermaid.CardinalityExactlyOne
+		if r.Nullable {
+			toCardinality = mermaid.CardinalityZeroOrOne
+		}
+		elements.Add(mermaid.Relationship(r.To.ID, r.From.ID, toCardinality, mermaid.CardinalityZeroOrMany, r.Column))
+	}
+	return mermaid.ERDiagram(mermaid.DiagramOptions{Title: s.Title}, elements.AsSlice()...).String()
+}
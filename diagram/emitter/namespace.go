@@ -0,0 +1,142 @@
+package emitter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+	"github.com/keisuke-m123/godiagramgen/mermaid"
+	"github.com/keisuke-m123/godiagramgen/plantuml"
+)
+
+type (
+	// packageNamespaces はパッケージのインポートパス毎に namespace 名を保持する。
+	packageNamespaces map[string]string
+)
+
+// newPackageNamespaces は図に登場するパッケージの namespace 名を決定する。
+//
+// namespace 名はインポートパスを pathToNamespace で変換したものとする。
+// d.ShortPackageNames が指定された場合はパッケージ名を使い、同名のパッケージが存在する場合のみインポートパスを使う。
+// 決定した namespace 名、または namespace 名と型名から classID で求めた異なるパッケージの型の識別子が一つでも重なる場合は、
+// パッケージ名を使わずに全てのパスを escapedPathToNamespace で変換する。
+func newPackageNamespaces(
+	d *model.Diagram,
+	pathToNamespace func(pkgPath string) string,
+	escapedPathToNamespace func(pkgPath string) string,
+	classID func(namespace, name string) string,
+) packageNamespaces {
+	refs := make(map[string]model.PackageRef)
+	for _, pkg := range d.Packages {
+		refs[pkg.Path] = pkg.PackageRef
+	}
+	for _, edge := range d.Edges() {
+		refs[edge.From.Package.Path] = edge.From.Package
+		refs[edge.To.Package.Path] = edge.To.Package
+	}
+	delete(refs, "")
+
+	pathCountsByName := make(map[string]int)
+	for _, ref := range refs {
+		pathCountsByName[ref.Name]++
+	}
+
+	ns := make(packageNamespaces)
+	for path, ref := range refs {
+		if d.ShortPackageNames && pathCountsByName[ref.Name] == 1 {
+			ns[path] = ref.Name
+		} else {
+			ns[path] = pathToNamespace(path)
+		}
+	}
+	if !ns.collides(d, classID) {
+		return ns
+	}
+
+	escaped := make(packageNamespaces)
+	for path := range refs {
+		escaped[path] = escapedPathToNamespace(path)
+	}
+	return escaped
+}
+
+// collides は異なるパッケージが同じ namespace 名となる、または異なるパッケージの型が classID で同じ識別子となるかを返す。
+func (ns packageNamespaces) collides(d *model.Diagram, classID func(namespace, name string) string) bool {
+	pathsByNamespace := make(map[string]string)
+	for path, namespace := range ns {
+		if other, ok := pathsByNamespace[namespace]; ok && other != path {
+			return true
+		}
+		pathsByNamespace[namespace] = path
+	}
+
+	var refs []model.NodeRef
+	for _, node := range d.Nodes() {
+		refs = append(refs, node.Ref())
+	}
+	for _, edge := range d.Edges() {
+		refs = append(refs, edge.From, edge.To)
+	}
+	// 同じパッケージの型の識別子が重なる場合は namespace 名によらないため、異なるパッケージの型の間でのみ比較する。
+	pathsByID := make(map[string]string)
+	for _, ref := range refs {
+		if ref.Package.Path == "" || ref.Name == "" {
+			continue
+		}
+		id := classID(ns.name(ref.Package), ref.Name)
+		if other, ok := pathsByID[id]; ok && other != ref.Package.Path {
+			return true
+		}
+		pathsByID[id] = ref.Package.Path
+	}
+	return false
+}
+
+// name は ref の namespace 名を返す。 builtin の型の場合は空文字を返す。
+func (ns packageNamespaces) name(ref model.PackageRef) string {
+	return ns[ref.Path]
+}
+
+// plantUMLClassID は PlantUML で namespace 内の型を参照する名前を返す。
+func plantUMLClassID(namespace, name string) string {
+	target := plantuml.NewRelationTargetWithNamespace(namespace, name)
+	return target.String()
+}
+
+// mermaidClassID は Mermaid の namespace 内の型の識別子を返す。
+func mermaidClassID(namespace, name string) string {
+	return mermaid.ClassID(namespace, strings.ReplaceAll(name, "*", ""))
+}
+
+// plantUMLNamespacePath はインポートパスを PlantUML のネストした namespace 名に変換する。
+//
+// . を取り除くため、 a.b/c と ab/c のように異なるパスが同じ namespace 名になる場合がある。
+func plantUMLNamespacePath(pkgPath string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(pkgPath, ".", ""), "/", ".")
+}
+
+// escapedPlantUMLNamespacePath はインポートパスを、異なるパスが必ず異なる名前となる PlantUML のネストした namespace 名に変換する。
+func escapedPlantUMLNamespacePath(pkgPath string) string {
+	return strings.ReplaceAll(escapeNamespacePath(pkgPath, "-/"), "/", ".")
+}
+
+// escapedMermaidNamespaceID はインポートパスを、異なるパスが必ず異なる名前となる Mermaid の namespace 名に変換する。
+func escapedMermaidNamespaceID(pkgPath string) string {
+	return escapeNamespacePath(pkgPath, "")
+}
+
+// escapeNamespacePath はインポートパスの英数字と keep に含まれる文字以外を、 _ と 2 桁の 16 進数の文字コードに置き換える。
+//
+// _ 自体も置き換えるため、異なるパスは必ず異なる文字列になる。
+func escapeNamespacePath(pkgPath string, keep string) string {
+	var b strings.Builder
+	for _, c := range []byte(pkgPath) {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', c != '_' && strings.IndexByte(keep, c) >= 0:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "_%02x", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,91 @@
+package emitter
+
+import (
+	"testing"
+
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+	"github.com/keisuke-m123/godiagramgen/mermaid"
+)
+
+func TestNewPackageNamespaces(t *testing.T) {
+	tests := []struct {
+		name     string
+		packages []*model.Package
+		short    bool
+		toName   func(string) string
+		escaped  func(string) string
+		classID  func(string, string) string
+		wantName map[string]string
+	}{
+		{
+			name:     "PlantUMLWithoutCollision",
+			packages: []*model.Package{newTestPackage("github.com/a/b", "b"), newTestPackage("github.com/a/c", "c")},
+			toName:   plantUMLNamespacePath,
+			escaped:  escapedPlantUMLNamespacePath,
+			classID:  plantUMLClassID,
+			wantName: map[string]string{"github.com/a/b": "githubcom.a.b", "github.com/a/c": "githubcom.a.c"},
+		},
+		{
+			name:     "PlantUMLWithCollision",
+			packages: []*model.Package{newTestPackage("a.b/c", "c"), newTestPackage("ab/c", "c"), newTestPackage("a_2eb/c", "c")},
+			toName:   plantUMLNamespacePath,
+			escaped:  escapedPlantUMLNamespacePath,
+			classID:  plantUMLClassID,
+			wantName: map[string]string{"a.b/c": "a_2eb.c", "ab/c": "ab.c", "a_2eb/c": "a_5f2eb.c"},
+		},
+		{
+			name:     "MermaidWithCollision",
+			packages: []*model.Package{newTestPackage("a-b/c", "c"), newTestPackage("a_b/c", "c"), newTestPackage("a/b/c", "c")},
+			toName:   mermaid.NamespaceID,
+			escaped:  escapedMermaidNamespaceID,
+			classID:  mermaidClassID,
+			wantName: map[string]string{"a-b/c": "a_2db_2fc", "a_b/c": "a_5fb_2fc", "a/b/c": "a_2fb_2fc"},
+		},
+		{
+			name:     "ShortNames",
+			packages: []*model.Package{newTestPackage("a/x", "x"), newTestPackage("b/x", "x"), newTestPackage("c/y", "y")},
+			short:    true,
+			toName:   mermaid.NamespaceID,
+			escaped:  escapedMermaidNamespaceID,
+			classID:  mermaidClassID,
+			wantName: map[string]string{"a/x": "a_x", "b/x": "b_x", "c/y": "y"},
+		},
+		{
+			name:     "ShortNameCollidesWithPath",
+			packages: []*model.Package{newTestPackage("a/x", "x"), newTestPackage("b/x", "x"), newTestPackage("c/a_x", "a_x")},
+			short:    true,
+			toName:   mermaid.NamespaceID,
+			escaped:  escapedMermaidNamespaceID,
+			classID:  mermaidClassID,
+			wantName: map[string]string{"a/x": "a_2fx", "b/x": "b_2fx", "c/a_x": "c_2fa_5fx"},
+		},
+		{
+			name:     "MermaidClassIDCollision",
+			packages: []*model.Package{newTestPackage("a", "a", "b_c"), newTestPackage("a_b", "a_b", "c")},
+			toName:   mermaid.NamespaceID,
+			escaped:  escapedMermaidNamespaceID,
+			classID:  mermaidClassID,
+			wantName: map[string]string{"a": "a", "a_b": "a_5fb"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := &model.Diagram{Packages: test.packages, ShortPackageNames: test.short}
+			ns := newPackageNamespaces(d, test.toName, test.escaped, test.classID)
+			for path, want := range test.wantName {
+				if got := ns.name(model.PackageRef{Path: path}); got != want {
+					t.Errorf("name(%s) = %s, want %s", path, got, want)
+				}
+			}
+		})
+	}
+}
+
+func newTestPackage(path, name string, nodeNames ...string) *model.Package {
+	pkg := &model.Package{PackageRef: model.PackageRef{Path: path, Name: name}}
+	for _, nodeName := range nodeNames {
+		pkg.Nodes = append(pkg.Nodes, &model.Node{Package: pkg.PackageRef, Name: nodeName})
+	}
+	return pkg
+}
@@ -0,0 +1,56 @@
+package emitter
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+)
+
+// originColors はパッケージ図で標準ライブラリと他のモジュールのパッケージを区別する背景色。
+var originColors = map[model.PackageOrigin]string{
+	model.PackageOriginStandard:   "#EEEEEE",
+	model.PackageOriginThirdParty: "#DDEEFF",
+}
+
+// violationColor はパッケージ図で import の循環や規則に違反する関連を強調する色。
+const violationColor = "#FF0000"
+
+// packageOrigins は図に含まれるパッケージのパスと提供元の対応を返す。
+// 図に含まれないパッケージは同じモジュールのパッケージとして扱う。
+func packageOrigins(d *model.Diagram) map[string]model.PackageOrigin {
+	origins := make(map[string]model.PackageOrigin)
+	for _, pkg := range d.Packages {
+		origins[pkg.Path] = pkg.Origin
+	}
+	return origins
+}
+
+// heatColors は Heat の 0, 0.5, 1 に対応する色(緑、黄、赤)。間の値は線形に補間する。
+var heatColors = [3][3]float64{
+	{0x63, 0xBE, 0x7B},
+	{0xFF, 0xEB, 0x84},
+	{0xF8, 0x69, 0x6B},
+}
+
+// packageFillColor はパッケージを塗りつぶす色を返す。
+// Heat が設定されている場合は Heat を表す色、それ以外は提供元を表す色とし、塗りつぶさない場合は空文字列を返す。
+func packageFillColor(pkg *model.Package) string {
+	if pkg.Heat != nil {
+		return heatColor(*pkg.Heat)
+	}
+	return originColors[pkg.Origin]
+}
+
+func heatColor(heat float64) string {
+	heat = math.Max(0, math.Min(1, heat))
+	from, to, ratio := heatColors[0], heatColors[1], heat*2
+	if heat > 0.5 {
+		from, to, ratio = heatColors[1], heatColors[2], (heat-0.5)*2
+	}
+	var rgb [3]int
+	for i := range rgb {
+		rgb[i] = int(math.Round(from[i] + (to[i]-from[i])*ratio))
+	}
+	return fmt.Sprintf("#%02X%02X%02X", rgb[0], rgb[1], rgb[2])
+}
@@ -0,0 +1,261 @@
+package emitter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+	"github.com/keisuke-m123/godiagramgen/plantuml"
+)
+
+type (
+	// PlantUMLClassEmitter はクラス図を PlantUML 形式に変換する。
+	PlantUMLClassEmitter struct{}
+)
+
+func NewPlantUMLClassEmitter() *PlantUMLClassEmitter {
+	return &PlantUMLClassEmitter{}
+}
+
+func (e *PlantUMLClassEmitter) Emit(d *model.Diagram) string {
+	return e.EmitResult(d).String()
+}
+
+// EmitResult は Emit と同じ内容を plantuml.Result として返す。
+func (e *PlantUMLClassEmitter) EmitResult(d *model.Diagram) *plantuml.Result {
+	elements := plantuml.NewElementStore()
+	if d.Theme != "" {
+		elements.Add(plantuml.Theme(d.Theme))
+	}
+	if d.Title != "" {
+		elements.Add(plantuml.Title(d.Title))
+	}
+	if legend := plantUMLLegend(d); legend != "" {
+		elements.Add(plantuml.Legend(legend))
+	}
+
+	ns := newPackageNamespaces(d, plantUMLNamespacePath, escapedPlantUMLNamespacePath, plantUMLClassID)
+	for _, pkg := range d.Packages {
+		classes := plantuml.NewElementStore()
+		for _, node := range pkg.Nodes {
+			classes.Add(e.buildNode(node))
+		}
+		elements.Add(plantuml.NamespaceWithOption(
+			ns.name(pkg.PackageRef),
+			plantuml.NamespaceOptions{Color: changeBackgroundColor(pkg.Status)},
+			classes.AsSlice()...,
+		))
+		for _, node := range pkg.Nodes {
+			if text := e.noteText(node); text != "" {
+				elements.Add(plantuml.NoteOf(e.relationTarget(ns, node.Ref()), plantuml.NotePositionTop, text))
+			}
+		}
+		for _, edge := range pkg.Edges {
+			elements.Add(e.buildEdge(ns, edge))
+		}
+	}
+
+	return plantuml.PlantUML(elements.AsSlice()...)
+}
+
+func (e *PlantUMLClassEmitter) buildNode(node *model.Node) plantuml.Element {
+	members := e.buildMembers(node)
+	color := changeBackgroundColor(node.Status)
+	switch node.Kind {
+	case model.NodeKindStruct:
+		return plantuml.ClassWithOption(
+			node.DisplayName(),
+			plantuml.ClassOptions{As: e.as(node), Spot: e.spot('S', "#7FFFD4"), Color: color},
+			members...,
+		)
+	case model.NodeKindInterface:
+		return plantuml.InterfaceWithOption(node.DisplayName(), plantuml.InterfaceOptions{As: e.as(node), Color: color}, members...)
+	case model.NodeKindConstraint:
+		// ~ は PlantUML の creole のエスケープ文字であるため、 ~~ に置き換える。
+		typeSet := strings.ReplaceAll(strings.Join(node.TypeSet, "; "), "~", "~~")
+		return plantuml.InterfaceWithOption(
+			node.DisplayName(),
+			plantuml.InterfaceOptions{
+				As:         e.as(node),
+				Spot:       e.spot('C', "#B0C4DE"),
+				Stereotype: plantuml.Stereotype(typeSet),
+				Color:      color,
+			},
+			members...,
+		)
+	case model.NodeKindDefinedType:
+		var stereotype string
+		if node.Underlying != "" {
+			stereotype = fmt.Sprintf("type of __%s__", node.Underlying)
+		}
+		return plantuml.ClassWithOption(
+			node.DisplayName(),
+			plantuml.ClassOptions{
+				As:         e.as(node),
+				Stereotype: plantuml.Stereotype(stereotype),
+				Spot:       e.spot('D', "#FF7700"),
+				Color:      color,
+			},
+			members...,
+		)
+	case model.NodeKindTypeAlias:
+		var stereotype string
+		if node.Underlying != "" {
+			stereotype = fmt.Sprintf("alias of __%s__", node.Underlying)
+		}
+		return plantuml.ClassWithOption(
+			node.Name,
+			plantuml.ClassOptions{
+				Stereotype: plantuml.Stereotype(stereotype),
+				Spot:       e.spot('T', "#EDDC44"),
+				Color:      color,
+			},
+			members...,
+		)
+	case model.NodeKindPackageFunctions:
+		return plantuml.ClassWithOption(
+			node.Name,
+			plantuml.ClassOptions{Spot: e.spot('F', "#6495ED"), Color: color},
+			members...,
+		)
+	case model.NodeKindPackageVariables:
+		return plantuml.ClassWithOption(
+			node.Name,
+			plantuml.ClassOptions{Spot: e.spot('V', "#6495ED"), Color: color},
+			members...,
+		)
+	default:
+		return plantuml.ClassWithOption(
+			node.DisplayName(),
+			plantuml.ClassOptions{
+				As:    node.Name,
+				Spot:  e.spot(underlyingTypeSpotName(node.DisplayName()), "#3CB371"),
+				Color: color,
+			},
+			members...,
+		)
+	}
+}
+
+// as は型パラメータなどにより表示名が Name と異なる場合に、関連から参照するための Name を返す。
+func (e *PlantUMLClassEmitter) as(node *model.Node) string {
+	if node.DisplayName() == node.Name {
+		return ""
+	}
+	return node.Name
+}
+
+func (e *PlantUMLClassEmitter) spot(name rune, hexColor string) plantuml.Spot {
+	color, _ := plantuml.ParseHexColor(hexColor)
+	return plantuml.Spot{Name: name, Color: color}
+}
+
+func (e *PlantUMLClassEmitter) buildMembers(node *model.Node) []plantuml.Element {
+	elements := plantuml.NewElementStore()
+	for _, c := range node.Constants {
+		elements.Add(plantuml.ConstantWithOption(c.Name, c.Value, e.memberOptions(c.Status)))
+	}
+	for _, f := range node.Fields {
+		options := e.memberOptions(f.Status)
+		options.Tag = f.Tag
+		elements.Add(plantuml.FieldWithOption(e.accessModifier(f.Exported), f.Name, f.Type, options))
+	}
+	for _, m := range node.Methods {
+		params := make(plantuml.Params, 0)
+		for _, p := range m.Params {
+			params = append(params, plantuml.Param{Name: p.Name, Type: p.Type})
+		}
+		returnValues := make(plantuml.ReturnValues, 0)
+		for _, r := range m.Results {
+			returnValues = append(returnValues, plantuml.ReturnValue{Type: r.Type})
+		}
+		elements.Add(plantuml.MethodWithOption(
+			e.accessModifier(m.Exported),
+			m.DisplayName(),
+			params,
+			returnValues,
+			e.memberOptions(m.Status),
+		))
+	}
+	return elements.AsSlice()
+}
+
+// noteText は型のドキュメントコメントと、ドキュメントコメントを持つメンバーの一覧を注釈の本文として返す。
+// ドキュメントコメントが一つも無い場合は空文字列を返す。
+func (e *PlantUMLClassEmitter) noteText(node *model.Node) string {
+	var members []string
+	for _, f := range node.Fields {
+		if f.Doc != "" {
+			members = append(members, fmt.Sprintf("**%s**: %s", f.Name, f.Doc))
+		}
+	}
+	for _, m := range node.Methods {
+		if m.Doc != "" {
+			members = append(members, fmt.Sprintf("**%s()**: %s", m.Name, m.Doc))
+		}
+	}
+
+	var sections []string
+	if node.Doc != "" {
+		sections = append(sections, node.Doc)
+	}
+	if len(members) > 0 {
+		sections = append(sections, strings.Join(members, "\n"))
+	}
+	return strings.Join(sections, "\n----\n")
+}
+
+func (e *PlantUMLClassEmitter) memberOptions(status model.ChangeStatus) plantuml.MemberOptions {
+	return plantuml.MemberOptions{Color: changeForegroundColor(status)}
+}
+
+func (e *PlantUMLClassEmitter) accessModifier(exported bool) plantuml.AccessModifier {
+	if exported {
+		return plantuml.AccessModifierPublic
+	}
+	return plantuml.AccessModifierPrivate
+}
+
+func (e *PlantUMLClassEmitter) buildEdge(ns packageNamespaces, edge *model.Edge) plantuml.Element {
+	from := e.relationTarget(ns, edge.From)
+	to := e.relationTarget(ns, edge.To)
+	options := plantuml.RelationOptions{
+		Label:            edge.Label,
+		Color:            changeForegroundColor(edge.Status),
+		FromMultiplicity: edge.FromMultiplicity,
+		ToMultiplicity:   edge.ToMultiplicity,
+	}
+	switch edge.Kind {
+	case model.EdgeKindExtension:
+		return plantuml.RelationWithOption(from, to, plantuml.RelationTypeExtension, options)
+	case model.EdgeKindComposition:
+		return plantuml.RelationWithOption(from, to, plantuml.RelationTypeComposition, options)
+	case model.EdgeKindAggregation:
+		// from と to を入れ替えるため、多重度も入れ替える。
+		options.FromMultiplicity, options.ToMultiplicity = edge.ToMultiplicity, edge.FromMultiplicity
+		return plantuml.RelationWithOption(to, from, plantuml.RelationTypeAggregation, options)
+	case model.EdgeKindAlias:
+		return plantuml.RelationWithOption(from, to, plantuml.RelationTypeAlias, options)
+	case model.EdgeKindCreation, model.EdgeKindDependency:
+		return plantuml.RelationWithOption(from, to, plantuml.RelationTypeDependency, options)
+	default:
+		return plantuml.RelationWithOption(from, to, plantuml.RelationTypeArrow, options)
+	}
+}
+
+func (e *PlantUMLClassEmitter) relationTarget(ns packageNamespaces, ref model.NodeRef) plantuml.RelationTarget {
+	return plantuml.NewRelationTargetWithNamespace(ns.name(ref.Package), ref.Name)
+}
+
+func underlyingTypeSpotName(typeName string) rune {
+	switch {
+	case strings.HasPrefix(typeName, "[]"):
+		return 's'
+	case strings.HasPrefix(typeName, "map"):
+		return 'm'
+	case strings.HasPrefix(typeName, "func"):
+		return 'f'
+	default:
+		return '-'
+	}
+}
@@ -0,0 +1,60 @@
+package emitter
+
+import (
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+	"github.com/keisuke-m123/godiagramgen/plantuml"
+)
+
+type (
+	// PlantUMLERDEmitter はエンティティ関連図を PlantUML 形式に変換する。
+	//
+	// 主キーの列は区切り線の上に出力し、 NULL を許容しない列には * を付ける。
+	PlantUMLERDEmitter struct{}
+)
+
+func NewPlantUMLERDEmitter() *PlantUMLERDEmitter {
+	return &PlantUMLERDEmitter{}
+}
+
+func (e *PlantUMLERDEmitter) Emit(s *model.Schema) string {
+	elements := plantuml.NewElementStore()
+	if s.Title != "" {
+		elements.Add(plantuml.Title(s.Title))
+	}
+	for _, entity := range s.Entities {
+		var keys, columns []plantuml.Element
+		for _, c := range entity.Columns {
+			column := plantuml.Column(c.Name, c.Type, plantuml.ColumnOptions{
+				Mandatory:  !c.Nullable,
+				Stereotype: e.stereotype(c),
+			})
+			if c.PrimaryKey {
+				keys = append(keys, column)
+			} else {
+				columns = append(columns, column)
+			}
+		}
+		elements.Add(plantuml.Entity(entity.Table, plantuml.EntityOptions{As: entity.ID}, keys, columns))
+	}
+	for _, r := range s.Relationships {
+		toCardinality := plantuml.CardinalityExactlyOne
+		if r.Nullable {
+			toCardinality = plantuml.CardinalityZeroOrOne
+		}
+		elements.Add(plantuml.EntityRelationship(r.To.ID, r.From.ID, toCardinality, plantuml.CardinalityZeroOrMany, r.Column))
+	}
+	return plantuml.PlantUML(elements.AsSlice()...).String()
+}
+
+func (e *PlantUMLERDEmitter) stereotype(c *model.Column) plantuml.Stereotype {
+	switch {
+	case c.PrimaryKey && c.ForeignKey:
+		return "PK, FK"
+	case c.PrimaryKey:
+		return "PK"
+	case c.ForeignKey:
+		return "FK"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,87 @@
+package emitter
+
+import (
+	"strings"
+
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+	"github.com/keisuke-m123/godiagramgen/plantuml"
+)
+
+type (
+	// PlantUMLPackageEmitter はパッケージ図を PlantUML 形式に変換する。
+	//
+	// パッケージはパスのセグメント毎にネストした namespace として出力する。
+	PlantUMLPackageEmitter struct{}
+)
+
+func NewPlantUMLPackageEmitter() *PlantUMLPackageEmitter {
+	return &PlantUMLPackageEmitter{}
+}
+
+func (e *PlantUMLPackageEmitter) Emit(d *model.Diagram) string {
+	elements := plantuml.NewElementStore()
+	if d.Theme != "" {
+		elements.Add(plantuml.Theme(d.Theme))
+	}
+	if d.HasChanges() {
+		elements.Add(plantuml.Legend(plantUMLLegend(d)))
+	}
+	ns := newPackageNamespaces(d, plantUMLNamespacePath, escapedPlantUMLNamespacePath, plantUMLClassID)
+	for _, pkg := range d.Packages {
+		elements.Add(e.buildNamespace(ns.name(pkg.PackageRef), e.namespaceColor(pkg)))
+		for _, edge := range pkg.Edges {
+			elements.Add(e.buildNamespace(ns.name(edge.To.Package), nil))
+		}
+	}
+	origins := packageOrigins(d)
+	for _, edge := range d.Edges() {
+		// 標準ライブラリと他のモジュールの import は点線とする。
+		relationType := plantuml.RelationTypeArrow
+		if origins[edge.To.Package.Path] != model.PackageOriginModule {
+			relationType = plantuml.RelationTypeDependency
+		}
+		elements.Add(plantuml.RelationWithOption(
+			plantuml.NewRelationTarget(ns.name(edge.From.Package)),
+			plantuml.NewRelationTarget(ns.name(edge.To.Package)),
+			relationType,
+			plantuml.RelationOptions{Color: e.relationColor(edge)},
+		))
+	}
+	return plantuml.PlantUML(elements.AsSlice()...).String()
+}
+
+// relationColor は違反を表す関連は赤、差分の図では変更の種類を表す線の色を返す。
+func (e *PlantUMLPackageEmitter) relationColor(edge *model.Edge) *plantuml.Color {
+	if edge.Violation {
+		color, _ := plantuml.ParseHexColor(violationColor)
+		return color
+	}
+	return changeForegroundColor(edge.Status)
+}
+
+// namespaceColor は差分の図では変更の種類、それ以外ではメトリクスまたはパッケージの提供元を表す背景色を返す。
+func (e *PlantUMLPackageEmitter) namespaceColor(pkg *model.Package) *plantuml.Color {
+	if color := changeBackgroundColor(pkg.Status); color != nil {
+		return color
+	}
+	hexColor := packageFillColor(pkg)
+	if hexColor == "" {
+		return nil
+	}
+	color, _ := plantuml.ParseHexColor(hexColor)
+	return color
+}
+
+// buildNamespace は . で区切られた namespace 名をネストした namespace に変換する。 color はパッケージ自体を表す最も内側の namespace の背景色とする。
+func (e *PlantUMLPackageEmitter) buildNamespace(name string, color *plantuml.Color) plantuml.Element {
+	ps := strings.Split(name, ".")
+	var ns plantuml.Element
+	for i := len(ps) - 1; i >= 0; i-- {
+		if ns == nil {
+			ns = plantuml.NamespaceWithOption(ps[i], plantuml.NamespaceOptions{Color: color})
+		} else {
+			ns = plantuml.Namespace(ps[i], ns)
+		}
+	}
+	return ns
+}
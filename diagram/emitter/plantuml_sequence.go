@@ -0,0 +1,57 @@
+package emitter
+
+import (
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+	"github.com/keisuke-m123/godiagramgen/plantuml"
+)
+
+type (
+	// PlantUMLSequenceEmitter はシーケンス図を PlantUML 形式に変換する。
+	//
+	// 呼び出された関数の中での呼び出しは、呼び出し先を活性化した間のメッセージとしてネストして出力する。
+	PlantUMLSequenceEmitter struct{}
+)
+
+func NewPlantUMLSequenceEmitter() *PlantUMLSequenceEmitter {
+	return &PlantUMLSequenceEmitter{}
+}
+
+func (e *PlantUMLSequenceEmitter) Emit(s *model.Sequence) string {
+	elements := plantuml.NewElementStore()
+	if s.Title != "" {
+		elements.Add(plantuml.Title(s.Title))
+	}
+	for _, p := range s.Participants {
+		elements.Add(plantuml.Participant(p.DisplayName(), plantuml.ParticipantOptions{
+			As:         p.ID,
+			Stereotype: e.stereotype(p),
+		}))
+	}
+	if s.Entry != nil {
+		elements.Add(e.buildMessage(s.Entry))
+	}
+	return plantuml.PlantUML(elements.AsSlice()...).String()
+}
+
+func (e *PlantUMLSequenceEmitter) stereotype(p *model.Participant) plantuml.Stereotype {
+	switch p.Kind {
+	case model.ParticipantKindInterface:
+		return "interface"
+	case model.ParticipantKindPackage:
+		return "package"
+	default:
+		return ""
+	}
+}
+
+func (e *PlantUMLSequenceEmitter) buildMessage(m *model.Message) plantuml.Element {
+	var from string
+	if m.From != nil {
+		from = m.From.ID
+	}
+	elements := make([]plantuml.Element, 0, len(m.Messages))
+	for _, child := range m.Messages {
+		elements = append(elements, e.buildMessage(child))
+	}
+	return plantuml.MessageWithOption(from, m.To.ID, m.Label, plantuml.MessageOptions{Async: m.Async}, elements...)
+}
@@ -0,0 +1,274 @@
+// Package export はクラス図の解析結果を外部のツールから利用するための JSON スキーマを提供する。
+//
+// スキーマに互換性のない変更を加える場合は SchemaVersion を更新する。
+package export
+
+import (
+	"sort"
+
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+)
+
+// SchemaVersion は Document のスキーマのバージョン。
+const SchemaVersion = "1"
+
+const (
+	RelationKindExtension   RelationKind = "extension"
+	RelationKindComposition RelationKind = "composition"
+	RelationKindAggregation RelationKind = "aggregation"
+	RelationKindAlias       RelationKind = "alias"
+	RelationKindImport      RelationKind = "import"
+	RelationKindCreation    RelationKind = "creation"
+	RelationKindDependency  RelationKind = "dependency"
+)
+
+type (
+	// Document は JSON として出力される解析結果全体を表す。
+	Document struct {
+		SchemaVersion string      `json:"schemaVersion"`
+		Title         string      `json:"title,omitempty"`
+		Packages      []*Package  `json:"packages"`
+		Relations     []*Relation `json:"relations"`
+	}
+
+	// Package はパッケージと、パッケージ内で宣言された型の一覧を表す。
+	Package struct {
+		Path         string  `json:"path"`
+		Name         string  `json:"name"`
+		Structs      []*Type `json:"structs"`
+		Interfaces   []*Type `json:"interfaces"`
+		DefinedTypes []*Type `json:"definedTypes"`
+		TypeAliases  []*Type `json:"typeAliases"`
+		// Functions はパッケージレベルの関数の一覧。関数の描画が指定された場合にのみ出力される。
+		Functions []*Method `json:"functions,omitempty"`
+		// Variables はパッケージレベルの変数の一覧。変数の描画が指定された場合にのみ出力される。
+		Variables []*Field `json:"variables,omitempty"`
+	}
+
+	// Type は struct, interface, defined type, type alias のいずれかを表す。
+	Type struct {
+		Name string `json:"name"`
+		// Underlying は defined type と type alias の場合に、基底となる型名が設定される。
+		Underlying string `json:"underlying,omitempty"`
+		// TypeParams はジェネリックな型の場合に、型パラメータが宣言順に設定される。
+		TypeParams []*TypeParam `json:"typeParams,omitempty"`
+		// TypeSet は型パラメータの制約にのみ使える interface の場合に、型集合を表す union が設定される。
+		TypeSet []string `json:"typeSet,omitempty"`
+		// Constants は defined type の場合に、その型の exported な定数が宣言順に設定される。
+		Constants []*Constant `json:"constants,omitempty"`
+		Fields    []*Field    `json:"fields,omitempty"`
+		Methods   []*Method   `json:"methods,omitempty"`
+		// Doc はドキュメントコメントの描画が指定された場合に、型のドキュメントコメントが設定される。
+		Doc string `json:"doc,omitempty"`
+	}
+
+	TypeParam struct {
+		Name       string `json:"name"`
+		Constraint string `json:"constraint"`
+	}
+
+	// Constant は定数と、その値を Go の定数式として表した文字列を表す。
+	Constant struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+
+	Field struct {
+		Name     string `json:"name"`
+		Type     string `json:"type"`
+		Exported bool   `json:"exported"`
+		Embedded bool   `json:"embedded"`
+		// Tag は struct tag の描画が指定された場合に、描画する struct tag が設定される。
+		Tag string `json:"tag,omitempty"`
+		Doc string `json:"doc,omitempty"`
+	}
+
+	Method struct {
+		Name     string `json:"name"`
+		Exported bool   `json:"exported"`
+		// TypeParams はジェネリックな関数の場合に、型パラメータが宣言順に設定される。
+		TypeParams []*TypeParam `json:"typeParams,omitempty"`
+		Params     []*Parameter `json:"params"`
+		Results    []*Parameter `json:"results"`
+		Doc        string       `json:"doc,omitempty"`
+	}
+
+	// Parameter はメソッドの引数または戻り値を表す。名前のない場合 Name は空になる。
+	Parameter struct {
+		Name string `json:"name,omitempty"`
+		Type string `json:"type"`
+	}
+
+	RelationKind string
+
+	// Relation は型の間の関連を表す。
+	Relation struct {
+		Kind RelationKind `json:"kind"`
+		From *Ref         `json:"from"`
+		To   *Ref         `json:"to"`
+		// Label は creation の場合に型を生成する関数名が、 aggregation の場合にフィールド名が設定される。
+		Label string `json:"label,omitempty"`
+		// FromMultiplicity と ToMultiplicity は aggregation と composition の場合に、 from と to の側の多重度が設定される。
+		FromMultiplicity string `json:"fromMultiplicity,omitempty"`
+		ToMultiplicity   string `json:"toMultiplicity,omitempty"`
+	}
+
+	// Ref は関連の端点となる型を表す。
+	// Package が空の場合は builtin の型、Name が空の場合はパッケージ自体を表す。
+	Ref struct {
+		Package     string `json:"package,omitempty"`
+		PackageName string `json:"packageName,omitempty"`
+		Name        string `json:"name,omitempty"`
+	}
+)
+
+// NewDocument は図のモデルから Document を生成する。
+//
+// モデル中の defined type の基底の型を表す補助的なノードは、 defined type の Underlying と関連の端点の型名に置き換える。
+func NewDocument(d *model.Diagram) *Document {
+	underlyingTypeNames := make(map[model.NodeRef]string)
+	for _, node := range d.Nodes() {
+		if node.Kind == model.NodeKindUnderlyingType {
+			underlyingTypeNames[node.Ref()] = node.DisplayName()
+		}
+	}
+	underlyings := make(map[model.NodeRef]string)
+	for _, edge := range d.Edges() {
+		if edge.Kind == model.EdgeKindAlias {
+			underlyings[edge.From] = refName(edge.To, underlyingTypeNames)
+		}
+	}
+
+	doc := &Document{
+		SchemaVersion: SchemaVersion,
+		Title:         d.Title,
+		Packages:      make([]*Package, 0),
+		Relations:     make([]*Relation, 0),
+	}
+	for _, p := range d.Packages {
+		pkg := &Package{
+			Path:         p.Path,
+			Name:         p.Name,
+			Structs:      make([]*Type, 0),
+			Interfaces:   make([]*Type, 0),
+			DefinedTypes: make([]*Type, 0),
+			TypeAliases:  make([]*Type, 0),
+		}
+		for _, node := range p.Nodes {
+			typ := newType(node)
+			if typ.Underlying == "" {
+				typ.Underlying = underlyings[node.Ref()]
+			}
+			switch node.Kind {
+			case model.NodeKindStruct:
+				pkg.Structs = append(pkg.Structs, typ)
+			case model.NodeKindInterface, model.NodeKindConstraint:
+				pkg.Interfaces = append(pkg.Interfaces, typ)
+			case model.NodeKindDefinedType:
+				pkg.DefinedTypes = append(pkg.DefinedTypes, typ)
+			case model.NodeKindTypeAlias:
+				pkg.TypeAliases = append(pkg.TypeAliases, typ)
+			case model.NodeKindPackageFunctions:
+				pkg.Functions = append(pkg.Functions, typ.Methods...)
+			case model.NodeKindPackageVariables:
+				pkg.Variables = append(pkg.Variables, typ.Fields...)
+			}
+		}
+		doc.Packages = append(doc.Packages, pkg)
+
+		for _, edge := range p.Edges {
+			doc.Relations = append(doc.Relations, &Relation{
+				Kind:  newRelationKind(edge.Kind),
+				From:  newRef(edge.From, underlyingTypeNames),
+				To:    newRef(edge.To, underlyingTypeNames),
+				Label: edge.Label,
+
+				FromMultiplicity: edge.FromMultiplicity,
+				ToMultiplicity:   edge.ToMultiplicity,
+			})
+		}
+	}
+	sort.SliceStable(doc.Packages, func(i, j int) bool {
+		return doc.Packages[i].Path < doc.Packages[j].Path
+	})
+	return doc
+}
+
+func newType(node *model.Node) *Type {
+	typ := &Type{
+		Name:       node.Name,
+		Underlying: node.Underlying,
+		TypeSet:    node.TypeSet,
+		Doc:        node.Doc,
+	}
+	for _, tp := range node.TypeParams {
+		typ.TypeParams = append(typ.TypeParams, &TypeParam{Name: tp.Name, Constraint: tp.Constraint})
+	}
+	for _, c := range node.Constants {
+		typ.Constants = append(typ.Constants, &Constant{Name: c.Name, Value: c.Value})
+	}
+	for _, f := range node.Fields {
+		typ.Fields = append(typ.Fields, &Field{
+			Name:     f.Name,
+			Type:     f.Type,
+			Exported: f.Exported,
+			Embedded: f.Embedded,
+			Tag:      f.Tag,
+			Doc:      f.Doc,
+		})
+	}
+	for _, m := range node.Methods {
+		method := &Method{
+			Name:     m.Name,
+			Exported: m.Exported,
+			Params:   make([]*Parameter, 0),
+			Results:  make([]*Parameter, 0),
+			Doc:      m.Doc,
+		}
+		for _, tp := range m.TypeParams {
+			method.TypeParams = append(method.TypeParams, &TypeParam{Name: tp.Name, Constraint: tp.Constraint})
+		}
+		for _, p := range m.Params {
+			method.Params = append(method.Params, &Parameter{Name: p.Name, Type: p.Type})
+		}
+		for _, r := range m.Results {
+			method.Results = append(method.Results, &Parameter{Name: r.Name, Type: r.Type})
+		}
+		typ.Methods = append(typ.Methods, method)
+	}
+	return typ
+}
+
+func newRelationKind(kind model.EdgeKind) RelationKind {
+	switch kind {
+	case model.EdgeKindExtension:
+		return RelationKindExtension
+	case model.EdgeKindComposition:
+		return RelationKindComposition
+	case model.EdgeKindAggregation:
+		return RelationKindAggregation
+	case model.EdgeKindAlias:
+		return RelationKindAlias
+	case model.EdgeKindCreation:
+		return RelationKindCreation
+	case model.EdgeKindDependency:
+		return RelationKindDependency
+	default:
+		return RelationKindImport
+	}
+}
+
+func newRef(ref model.NodeRef, underlyingTypeNames map[model.NodeRef]string) *Ref {
+	return &Ref{
+		Package:     ref.Package.Path,
+		PackageName: ref.Package.Name,
+		Name:        refName(ref, underlyingTypeNames),
+	}
+}
+
+func refName(ref model.NodeRef, underlyingTypeNames map[model.NodeRef]string) string {
+	if name, ok := underlyingTypeNames[ref]; ok {
+		return name
+	}
+	return ref.Name
+}
@@ -0,0 +1,246 @@
+// Package filter はパッケージのインポートパスや型の名前、種類、公開されているかによって図に描画する要素を絞り込む。
+package filter
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+)
+
+const (
+	KindStruct      = "struct"
+	KindInterface   = "interface"
+	KindConstraint  = "constraint"
+	KindDefinedType = "defined"
+	KindTypeAlias   = "alias"
+)
+
+var kindMap = map[string]model.NodeKind{
+	KindStruct:      model.NodeKindStruct,
+	KindInterface:   model.NodeKindInterface,
+	KindConstraint:  model.NodeKindConstraint,
+	KindDefinedType: model.NodeKindDefinedType,
+	KindTypeAlias:   model.NodeKindTypeAlias,
+}
+
+type (
+	// Options はカンマ区切りの文字列で指定された絞り込みの条件を表す。
+	Options struct {
+		IncludePackages string
+		ExcludePackages string
+		IncludeTypes    string
+		ExcludeTypes    string
+		// Kinds は描画する型の種類を KindStruct などのカンマ区切りで指定する。
+		Kinds        string
+		ExportedOnly bool
+	}
+
+	// Filter は図に描画するパッケージと型を絞り込む。
+	//
+	// Include が空の場合は全てに一致するものとし、 Exclude は Include よりも優先する。
+	Filter struct {
+		includePackages Patterns
+		excludePackages Patterns
+		includeTypes    Patterns
+		excludeTypes    Patterns
+		kinds           map[model.NodeKind]struct{}
+		exportedOnly    bool
+		// exportedMembersOnly は型のフィールドとメソッド、パッケージレベルの関数と変数も exported なもののみとする。
+		exportedMembersOnly bool
+	}
+)
+
+// NewAPISurfaceFilter は exported な型とメンバーのみを残し、パッケージの公開 API を表す図とする Filter を返す。
+func NewAPISurfaceFilter() *Filter {
+	return &Filter{
+		exportedOnly:        true,
+		exportedMembersOnly: true,
+	}
+}
+
+func NewFilter(options Options) (*Filter, error) {
+	var err error
+	f := &Filter{exportedOnly: options.ExportedOnly}
+	if f.includePackages, err = ParsePatterns(options.IncludePackages); err != nil {
+		return nil, err
+	}
+	if f.excludePackages, err = ParsePatterns(options.ExcludePackages); err != nil {
+		return nil, err
+	}
+	if f.includeTypes, err = ParsePatterns(options.IncludeTypes); err != nil {
+		return nil, err
+	}
+	if f.excludeTypes, err = ParsePatterns(options.ExcludeTypes); err != nil {
+		return nil, err
+	}
+	if f.kinds, err = parseKinds(options.Kinds); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func parseKinds(list string) (map[model.NodeKind]struct{}, error) {
+	kinds := make(map[model.NodeKind]struct{})
+	for _, s := range strings.Split(list, ",") {
+		trimmed := strings.TrimSpace(s)
+		if trimmed == "" {
+			continue
+		}
+		kind, ok := kindMap[trimmed]
+		if !ok {
+			return nil, fmt.Errorf("unsupported kind %s", trimmed)
+		}
+		kinds[kind] = struct{}{}
+	}
+	return kinds, nil
+}
+
+// Apply は d から条件に一致しないパッケージと型、それらを端点とする関連を取り除いた図を返す。
+//
+// 関数や変数をまとめたノードはパッケージの条件のみで判定する。
+// exported なメンバーのみとする場合は、メンバーを一つも持たなくなった関数や変数をまとめたノードも取り除く。
+// defined type の基底の型を表すノードは、残った関連から参照されない場合に取り除く。
+func (f *Filter) Apply(d *model.Diagram) *model.Diagram {
+	removed := &removedNodes{
+		removed: make(map[model.NodeRef]struct{}),
+		all:     make(map[model.NodeRef]struct{}),
+	}
+	for _, node := range d.Nodes() {
+		removed.all[node.Ref()] = struct{}{}
+	}
+	var packages []*model.Package
+	for _, pkg := range d.Packages {
+		if !f.keepPackage(pkg.PackageRef) {
+			for _, node := range pkg.Nodes {
+				removed.removed[node.Ref()] = struct{}{}
+			}
+			continue
+		}
+		p := &model.Package{PackageRef: pkg.PackageRef, Edges: pkg.Edges}
+		for _, node := range pkg.Nodes {
+			if f.exportedMembersOnly {
+				node = exportedMembers(node)
+			}
+			if f.keepNode(node) {
+				p.Nodes = append(p.Nodes, node)
+			} else {
+				removed.removed[node.Ref()] = struct{}{}
+			}
+		}
+		packages = append(packages, p)
+	}
+
+	referenced := make(map[model.NodeRef]struct{})
+	for _, p := range packages {
+		var edges []*model.Edge
+		for _, edge := range p.Edges {
+			if removed.contains(edge.From) || removed.contains(edge.To) {
+				continue
+			}
+			// コンストラクタからの関連は、コンストラクタが描画される場合のみ残す。
+			if f.exportedMembersOnly && edge.Kind == model.EdgeKindCreation && !token.IsExported(edge.Label) {
+				continue
+			}
+			edges = append(edges, edge)
+			referenced[edge.From] = struct{}{}
+			referenced[edge.To] = struct{}{}
+		}
+		p.Edges = edges
+	}
+
+	filtered := *d
+	filtered.Packages = nil
+	for _, p := range packages {
+		var nodes []*model.Node
+		for _, node := range p.Nodes {
+			if _, ok := referenced[node.Ref()]; ok || node.Kind != model.NodeKindUnderlyingType {
+				nodes = append(nodes, node)
+			}
+		}
+		p.Nodes = nodes
+		filtered.Packages = append(filtered.Packages, p)
+	}
+	return &filtered
+}
+
+func (f *Filter) keepPackage(pkg model.PackageRef) bool {
+	if len(f.includePackages) > 0 && !f.includePackages.MatchAny(pkg.Path) {
+		return false
+	}
+	return !f.excludePackages.MatchAny(pkg.Path)
+}
+
+func (f *Filter) keepNode(node *model.Node) bool {
+	if !isTypeNode(node) {
+		return !f.exportedMembersOnly || !isMemberContainer(node) || len(node.Fields)+len(node.Methods) > 0
+	}
+	if len(f.kinds) > 0 {
+		if _, ok := f.kinds[node.Kind]; !ok {
+			return false
+		}
+	}
+	if f.exportedOnly && !token.IsExported(node.Name) {
+		return false
+	}
+	if len(f.includeTypes) > 0 && !f.includeTypes.MatchAny(node.Name) {
+		return false
+	}
+	return !f.excludeTypes.MatchAny(node.Name)
+}
+
+// isTypeNode は node が Go の型として宣言されたノードかを判定する。
+func isTypeNode(node *model.Node) bool {
+	for _, kind := range kindMap {
+		if node.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// removedNodes は Apply で取り除いたノードを表す。
+type removedNodes struct {
+	removed map[model.NodeRef]struct{}
+	all     map[model.NodeRef]struct{}
+}
+
+// contains は ref が取り除いたノードを参照しているかを判定する。
+//
+// 埋め込みの関連はポインタを表す * を含む名前で参照するため、 ref がノードでない場合は * を取り除いた名前でも判定する。
+func (r *removedNodes) contains(ref model.NodeRef) bool {
+	if _, ok := r.removed[ref]; ok {
+		return true
+	}
+	if _, ok := r.all[ref]; ok {
+		return false
+	}
+	_, ok := r.removed[model.NodeRef{Package: ref.Package, Name: strings.TrimLeft(ref.Name, "*")}]
+	return ok
+}
+
+// isMemberContainer は node がパッケージレベルの関数や変数をまとめたノードかを判定する。
+func isMemberContainer(node *model.Node) bool {
+	return node.Kind == model.NodeKindPackageFunctions || node.Kind == model.NodeKindPackageVariables
+}
+
+// exportedMembers は node から unexported なフィールドとメソッドを取り除いたノードを返す。
+//
+// 埋め込まれたフィールドは埋め込まれた型が unexported な場合に取り除く。
+func exportedMembers(node *model.Node) *model.Node {
+	n := *node
+	n.Fields = nil
+	for _, field := range node.Fields {
+		if token.IsExported(field.Name) {
+			n.Fields = append(n.Fields, field)
+		}
+	}
+	n.Methods = nil
+	for _, method := range node.Methods {
+		if method.Exported {
+			n.Methods = append(n.Methods, method)
+		}
+	}
+	return &n
+}
@@ -0,0 +1,146 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RegexpPrefix はパターンを glob ではなく正規表現として扱うための接頭辞。
+const RegexpPrefix = "re:"
+
+type (
+	// Pattern はパッケージのインポートパスや型名に一致させるパターンを表す。
+	Pattern struct {
+		source string
+		re     *regexp.Regexp
+	}
+
+	Patterns []*Pattern
+)
+
+// newPattern は s をパターンとして解釈する。
+//
+// RegexpPrefix で始まる場合は残りを正規表現とし、それ以外は glob とする。
+// glob では * が / 以外の任意の文字列に、 ** が / を含む任意の文字列に、 ? が / 以外の任意の一文字に一致する。
+func newPattern(s string) (*Pattern, error) {
+	expr := globToRegexp(s)
+	if strings.HasPrefix(s, RegexpPrefix) {
+		expr = strings.TrimPrefix(s, RegexpPrefix)
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %s: %w", s, err)
+	}
+	return &Pattern{source: s, re: re}, nil
+}
+
+// ParsePatterns はカンマ区切りのパターンの一覧を解釈する。区切り方は SplitPatterns と同一とする。
+func ParsePatterns(list string) (Patterns, error) {
+	var patterns Patterns
+	for _, s := range SplitPatterns(list) {
+		p, err := newPattern(s)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, nil
+}
+
+// SplitPatterns はカンマ区切りのパターンの一覧を分割し、前後の空白を取り除いた空でないパターンを返す。
+//
+// RegexpPrefix 以降の正規表現では、 a{1,3} や (a|b,c) のような括弧 (), {}, [] の中のカンマと、 \, のようにエスケープしたカンマでは区切らない。
+func SplitPatterns(list string) []string {
+	var (
+		patterns []string
+		b        strings.Builder
+		// inRegexp は RegexpPrefix 以降であること、 depth は () と {} のネストの深さ、 inClass は [] の中であることを表す。
+		inRegexp bool
+		depth    int
+		inClass  bool
+	)
+	flush := func() {
+		if s := strings.TrimSpace(b.String()); s != "" {
+			patterns = append(patterns, s)
+		}
+		b.Reset()
+		inRegexp, depth, inClass = false, 0, false
+	}
+	for i := 0; i < len(list); i++ {
+		c := list[i]
+		if !inRegexp && strings.HasPrefix(list[i:], RegexpPrefix) {
+			inRegexp = true
+		}
+		switch {
+		case inRegexp && c == '\\' && i+1 < len(list):
+			b.WriteByte(c)
+			i++
+			c = list[i]
+		case inRegexp && inClass:
+			inClass = c != ']'
+		case inRegexp && c == '[':
+			inClass = true
+		case inRegexp && (c == '(' || c == '{'):
+			depth++
+		case inRegexp && (c == ')' || c == '}') && depth > 0:
+			depth--
+		case c == ',' && depth == 0:
+			flush()
+			continue
+		}
+		b.WriteByte(c)
+	}
+	flush()
+	return patterns
+}
+
+func (p *Pattern) String() string {
+	return p.source
+}
+
+func (p *Pattern) Match(s string) bool {
+	return p.re.MatchString(s)
+}
+
+// MatchAny は s がいずれかのパターンに一致するかを判定する。
+func (ps Patterns) MatchAny(s string) bool {
+	for _, p := range ps {
+		if p.Match(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// globToRegexp は glob を文字列全体に一致する正規表現に変換する。
+//
+// **/ は空文字列にも一致させ、末尾の /** はディレクトリ自体にも一致させる。(**/mocks/** が mocks にも一致する)
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); {
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(.*/)?")
+			i += 3
+		case glob[i:] == "/**":
+			b.WriteString("(/.*)?")
+			i += 3
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i += 2
+		case glob[i] == '*':
+			b.WriteString("[^/]*")
+			i++
+		case glob[i] == '?':
+			b.WriteString("[^/]")
+			i++
+		default:
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+			i++
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
@@ -0,0 +1,120 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGlobToRegexp(t *testing.T) {
+	tests := []struct {
+		name      string
+		glob      string
+		want      string
+		matches   []string
+		unmatches []string
+	}{
+		{
+			name:      "Star",
+			glob:      "*Service",
+			want:      `^[^/]*Service$`,
+			matches:   []string{"OrderService", "Service"},
+			unmatches: []string{"a/OrderService", "OrderServices"},
+		},
+		{
+			name:      "LeadingDoubleStar",
+			glob:      "**/mocks",
+			want:      `^(.*/)?mocks$`,
+			matches:   []string{"mocks", "a/mocks", "a/b/mocks"},
+			unmatches: []string{"amocks", "mocks/a"},
+		},
+		{
+			name:      "TrailingDoubleStar",
+			glob:      "github.com/a/**",
+			want:      `^github\.com/a(/.*)?$`,
+			matches:   []string{"github.com/a", "github.com/a/b", "github.com/a/b/c"},
+			unmatches: []string{"github.com/ab", "githubxcom/a"},
+		},
+		{
+			name:      "SurroundingDoubleStar",
+			glob:      "**/mocks/**",
+			want:      `^(.*/)?mocks(/.*)?$`,
+			matches:   []string{"mocks", "a/mocks", "a/mocks/b"},
+			unmatches: []string{"a/mocksb", "amocks/b"},
+		},
+		{
+			name:      "DoubleStarInSegment",
+			glob:      "a**b",
+			want:      `^a.*b$`,
+			matches:   []string{"ab", "a/x/b"},
+			unmatches: []string{"a/x/c"},
+		},
+		{
+			name:      "Question",
+			glob:      "v?",
+			want:      `^v[^/]$`,
+			matches:   []string{"v1", "v2"},
+			unmatches: []string{"v", "v10", "v/"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := globToRegexp(test.glob); got != test.want {
+				t.Errorf("globToRegexp(%s) = %s, want %s", test.glob, got, test.want)
+			}
+			p, err := newPattern(test.glob)
+			if err != nil {
+				t.Fatalf("newPattern(%s) error = %v", test.glob, err)
+			}
+			for _, s := range test.matches {
+				if !p.Match(s) {
+					t.Errorf("%s does not match %s", test.glob, s)
+				}
+			}
+			for _, s := range test.unmatches {
+				if p.Match(s) {
+					t.Errorf("%s matches %s", test.glob, s)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitPatterns(t *testing.T) {
+	tests := []struct {
+		name string
+		list string
+		want []string
+	}{
+		{name: "Globs", list: " *Service, ,**/mocks/** ", want: []string{"*Service", "**/mocks/**"}},
+		{name: "RegexpRepetition", list: "re:^a{1,3}$,*Service", want: []string{"re:^a{1,3}$", "*Service"}},
+		{name: "RegexpGroup", list: "re:(Order,Item)Service,re:x", want: []string{"re:(Order,Item)Service", "re:x"}},
+		{name: "RegexpClass", list: "re:[,(]x,y", want: []string{"re:[,(]x", "y"}},
+		{name: "RegexpEscapedComma", list: `re:a\,b,c`, want: []string{`re:a\,b`, "c"}},
+		{name: "Rules", list: "re:^a{1,2}$->**,b->c", want: []string{"re:^a{1,2}$->**", "b->c"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := SplitPatterns(test.list)
+			if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+				t.Errorf("SplitPatterns(%s) = %q, want %q", test.list, got, test.want)
+			}
+		})
+	}
+}
+
+func TestParsePatterns_Regexp(t *testing.T) {
+	patterns, err := ParsePatterns("re:^a{1,3}$")
+	if err != nil {
+		t.Fatalf("ParsePatterns() error = %v", err)
+	}
+	if len(patterns) != 1 {
+		t.Fatalf("ParsePatterns() = %v, want one pattern", patterns)
+	}
+	for s, want := range map[string]bool{"a": true, "aaa": true, "aaaa": false, "": false} {
+		if got := patterns.MatchAny(s); got != want {
+			t.Errorf("MatchAny(%q) = %t, want %t", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Package implementation は interface と、その interface を実装する型の対応を表す。
+package implementation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+)
+
+type (
+	// Entry は一つの interface と、その interface を実装する型を表す。
+	Entry struct {
+		Interface    model.NodeRef
+		Implementers []*Implementer
+	}
+
+	// Implementer は interface を実装する型を表す。
+	Implementer struct {
+		Type model.NodeRef
+		// PointerOnly は型のポインタでのみ interface を実装する(ポインタレシーバのメソッドを含む)ことを表す。
+		PointerOnly bool
+	}
+
+	jsonEntry struct {
+		Interface    string            `json:"interface"`
+		Implementers []jsonImplementer `json:"implementers"`
+	}
+
+	jsonImplementer struct {
+		Type        string `json:"type"`
+		PointerOnly bool   `json:"pointerOnly"`
+	}
+)
+
+// FullName は ref をインポートパスを含めた pkg/path.Name の形式で返す。 builtin の型は名前のみとする。
+func FullName(ref model.NodeRef) string {
+	if ref.Package.Path == "" {
+		return ref.Name
+	}
+	return ref.Package.Path + "." + ref.Name
+}
+
+// Sort は entries を interface の名前の順に、それぞれの実装する型を名前の順に並べる。
+func Sort(entries []*Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return strings.Compare(FullName(entries[i].Interface), FullName(entries[j].Interface)) < 0
+	})
+	for _, entry := range entries {
+		sort.SliceStable(entry.Implementers, func(i, j int) bool {
+			return strings.Compare(FullName(entry.Implementers[i].Type), FullName(entry.Implementers[j].Type)) < 0
+		})
+	}
+}
+
+// Filter は entries から interface が pkg.Name (pkg はインポートパスまたはパッケージ名) に一致するもののみを返す。
+func Filter(entries []*Entry, name string) ([]*Entry, error) {
+	var filtered []*Entry
+	for _, entry := range entries {
+		ref := entry.Interface
+		if name == FullName(ref) || (ref.Package.Name != "" && name == ref.Package.Name+"."+ref.Name) {
+			filtered = append(filtered, entry)
+		}
+	}
+	if len(filtered) == 0 {
+		return nil, fmt.Errorf("interface not found: %s", name)
+	}
+	return filtered, nil
+}
+
+// RenderText は interface 毎に、実装する型を字下げして列挙したテキストを返す。
+// ポインタでのみ実装する型は * を付けて表す。
+func RenderText(entries []*Entry) string {
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		buf.WriteString(FullName(entry.Interface))
+		buf.WriteString("\n")
+		if len(entry.Implementers) == 0 {
+			buf.WriteString("    (no implementations)\n")
+		}
+		for _, implementer := range entry.Implementers {
+			buf.WriteString("    ")
+			if implementer.PointerOnly {
+				buf.WriteString("*")
+			}
+			buf.WriteString(FullName(implementer.Type))
+			buf.WriteString("\n")
+		}
+	}
+	return buf.String()
+}
+
+// RenderJSON は interface と実装する型の対応を JSON の配列として返す。
+func RenderJSON(entries []*Entry) string {
+	jsonEntries := make([]jsonEntry, 0, len(entries))
+	for _, entry := range entries {
+		e := jsonEntry{Interface: FullName(entry.Interface), Implementers: []jsonImplementer{}}
+		for _, implementer := range entry.Implementers {
+			e.Implementers = append(e.Implementers, jsonImplementer{
+				Type:        FullName(implementer.Type),
+				PointerOnly: implementer.PointerOnly,
+			})
+		}
+		jsonEntries = append(jsonEntries, e)
+	}
+	b, err := json.MarshalIndent(jsonEntries, "", "  ")
+	if err != nil {
+		// jsonEntry は常に JSON に変換できる。
+		panic(err)
+	}
+	return string(b) + "\n"
+}
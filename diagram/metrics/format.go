@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+)
+
+// RenderTable はメトリクスを空白で揃えた表として返す。
+func RenderTable(packages []*Package) string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "PACKAGE\tCA\tCE\tI\tA\tD")
+	for _, p := range packages {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%d\t%.2f\t%.2f\t%.2f\n", p.Path, p.Afferent, p.Efferent, p.Instability, p.Abstractness, p.Distance)
+	}
+	_ = w.Flush()
+	return buf.String()
+}
+
+// RenderCSV はメトリクスをヘッダー付きの CSV として返す。
+func RenderCSV(packages []*Package) string {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"path", "name", "afferent", "efferent", "interfaces", "structs", "instability", "abstractness", "distance"})
+	for _, p := range packages {
+		_ = w.Write([]string{
+			p.Path,
+			p.Name,
+			strconv.Itoa(p.Afferent),
+			strconv.Itoa(p.Efferent),
+			strconv.Itoa(p.Interfaces),
+			strconv.Itoa(p.Structs),
+			formatFloat(p.Instability),
+			formatFloat(p.Abstractness),
+			formatFloat(p.Distance),
+		})
+	}
+	w.Flush()
+	return buf.String()
+}
+
+// RenderJSON はメトリクスを JSON の配列として返す。
+func RenderJSON(packages []*Package) string {
+	if packages == nil {
+		packages = []*Package{}
+	}
+	b, err := json.MarshalIndent(packages, "", "  ")
+	if err != nil {
+		// Package は常に JSON に変換できる。
+		panic(err)
+	}
+	return string(b) + "\n"
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 4, 64)
+}
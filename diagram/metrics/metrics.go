@@ -0,0 +1,157 @@
+// Package metrics はパッケージ図のモデルからパッケージ毎の結合度のメトリクスを計算する。
+//
+// 各メトリクスは Robert C. Martin のパッケージ設計の原則に基づく。
+//   - afferent (Ca): そのパッケージを import するパッケージの数
+//   - efferent (Ce): そのパッケージが import するパッケージの数
+//   - instability (I): Ce / (Ca + Ce)
+//   - abstractness (A): interface の数 / (interface の数 + struct の数)
+//   - distance (D): 主系列 A + I = 1 からの距離 |A + I - 1|
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+)
+
+const (
+	MetricAfferent     Metric = "afferent"
+	MetricEfferent     Metric = "efferent"
+	MetricInstability  Metric = "instability"
+	MetricAbstractness Metric = "abstractness"
+	MetricDistance     Metric = "distance"
+)
+
+type (
+	// Metric はメトリクスの名前を表す。
+	Metric string
+
+	// TypeCounts はパッケージで宣言された型の数を表す。
+	TypeCounts struct {
+		Interfaces int
+		Structs    int
+	}
+
+	// Package は一つのパッケージのメトリクスを表す。
+	Package struct {
+		Path         string  `json:"path"`
+		Name         string  `json:"name"`
+		Afferent     int     `json:"afferent"`
+		Efferent     int     `json:"efferent"`
+		Interfaces   int     `json:"interfaces"`
+		Structs      int     `json:"structs"`
+		Instability  float64 `json:"instability"`
+		Abstractness float64 `json:"abstractness"`
+		Distance     float64 `json:"distance"`
+	}
+)
+
+// Metrics は全てのメトリクスを表示する順で返す。
+func Metrics() []Metric {
+	return []Metric{MetricAfferent, MetricEfferent, MetricInstability, MetricAbstractness, MetricDistance}
+}
+
+// ParseMetric は s をメトリクスの名前として解釈する。
+func ParseMetric(s string) (Metric, error) {
+	for _, m := range Metrics() {
+		if string(m) == s {
+			return m, nil
+		}
+	}
+	var names []string
+	for _, m := range Metrics() {
+		names = append(names, string(m))
+	}
+	return "", fmt.Errorf("unsupported metric %s (%s)", s, strings.Join(names, ", "))
+}
+
+// Compute は d の import と counts の型の数から、 d の同じモジュールのパッケージ毎のメトリクスをパスの順で計算する。
+//
+// 結合度は d に含まれる import のみを数える。 counts はパッケージのパスをキーとする。
+func Compute(d *model.Diagram, counts map[string]TypeCounts) []*Package {
+	afferent := make(map[string]map[string]struct{})
+	efferent := make(map[string]map[string]struct{})
+	add := func(m map[string]map[string]struct{}, key, value string) {
+		if _, ok := m[key]; !ok {
+			m[key] = make(map[string]struct{})
+		}
+		m[key][value] = struct{}{}
+	}
+	for _, edge := range d.Edges() {
+		from, to := edge.From.Package.Path, edge.To.Package.Path
+		if from == to {
+			continue
+		}
+		add(efferent, from, to)
+		add(afferent, to, from)
+	}
+
+	var packages []*Package
+	for _, pkg := range d.Packages {
+		if pkg.Origin != model.PackageOriginModule {
+			continue
+		}
+		c := counts[pkg.Path]
+		p := &Package{
+			Path:       pkg.Path,
+			Name:       pkg.Name,
+			Afferent:   len(afferent[pkg.Path]),
+			Efferent:   len(efferent[pkg.Path]),
+			Interfaces: c.Interfaces,
+			Structs:    c.Structs,
+		}
+		if p.Afferent+p.Efferent > 0 {
+			p.Instability = float64(p.Efferent) / float64(p.Afferent+p.Efferent)
+		}
+		if c.Interfaces+c.Structs > 0 {
+			p.Abstractness = float64(c.Interfaces) / float64(c.Interfaces+c.Structs)
+		}
+		p.Distance = math.Abs(p.Abstractness + p.Instability - 1)
+		packages = append(packages, p)
+	}
+	sort.SliceStable(packages, func(i, j int) bool {
+		return strings.Compare(packages[i].Path, packages[j].Path) < 0
+	})
+	return packages
+}
+
+// Value は metric の値を返す。
+func (p *Package) Value(metric Metric) float64 {
+	switch metric {
+	case MetricAfferent:
+		return float64(p.Afferent)
+	case MetricEfferent:
+		return float64(p.Efferent)
+	case MetricInstability:
+		return p.Instability
+	case MetricAbstractness:
+		return p.Abstractness
+	default:
+		return p.Distance
+	}
+}
+
+// Heat は packages の metric の値を 0 から 1 に正規化し、 d のパッケージの Heat に設定する。
+//
+// instability, abstractness, distance はそのまま、 afferent と efferent は最大値で割った値とする。
+func Heat(d *model.Diagram, packages []*Package, metric Metric) {
+	max := 1.0
+	if metric == MetricAfferent || metric == MetricEfferent {
+		for _, p := range packages {
+			max = math.Max(max, p.Value(metric))
+		}
+	}
+	values := make(map[string]float64)
+	for _, p := range packages {
+		values[p.Path] = p.Value(metric) / max
+	}
+	for _, pkg := range d.Packages {
+		if v, ok := values[pkg.Path]; ok {
+			heat := v
+			pkg.Heat = &heat
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package model
+
+type (
+	// Schema はエンティティ関連図全体を表す。
+	Schema struct {
+		Title         string
+		Entities      []*Entity
+		Relationships []*Relationship
+	}
+
+	// Entity はテーブルとして扱う struct を表す。
+	Entity struct {
+		// ID は図の中でエンティティを一意に識別する名前。通常はテーブル名と同一とする。
+		ID    string
+		Table string
+		// Type はエンティティの元になった struct を表す。
+		Type    NodeRef
+		Columns []*Column
+	}
+
+	// Column はテーブルの列として扱うフィールドを表す。
+	Column struct {
+		Name       string
+		Type       string
+		PrimaryKey bool
+		ForeignKey bool
+		// Nullable はフィールドがポインタ、または sql.NullString などの NULL を表せる型であることを表す。
+		Nullable bool
+	}
+
+	// Relationship は外部キーによるエンティティ間の関連を表す。
+	// From は外部キーの列を持つエンティティ、 To は参照されるエンティティを表す。
+	Relationship struct {
+		From   *Entity
+		To     *Entity
+		Column string
+		// Nullable は外部キーの列が NULL を表せることを表す。参照されるエンティティは 0 または 1 となる。
+		Nullable bool
+	}
+)
@@ -0,0 +1,75 @@
+package model
+
+const (
+	// FocusDirectionBoth は関連の向きに関わらず辿ることを表す。
+	FocusDirectionBoth FocusDirection = iota
+	// FocusDirectionOutgoing は From から To の向きにのみ辿ることを表す。(注目する型が使う型)
+	FocusDirectionOutgoing
+	// FocusDirectionIncoming は To から From の向きにのみ辿ることを表す。(注目する型を使う型)
+	FocusDirectionIncoming
+)
+
+type (
+	// FocusDirection は Focus で関連を辿る向きを表す。
+	FocusDirection int
+)
+
+// Focus は ref のノードから depth 回以内の関連で辿れるノードと、それらの間の関連のみを持つ図を返す。
+//
+// ノードを一つも持たなくなったパッケージは取り除く。
+func (d *Diagram) Focus(ref NodeRef, depth int, direction FocusDirection) *Diagram {
+	reached := map[NodeRef]struct{}{ref: {}}
+	frontier := []NodeRef{ref}
+	edges := d.Edges()
+	for i := 0; i < depth && len(frontier) > 0; i++ {
+		var next []NodeRef
+		for _, current := range frontier {
+			for _, edge := range edges {
+				neighbor, ok := edge.neighbor(current, direction)
+				if !ok {
+					continue
+				}
+				if _, ok := reached[neighbor]; ok {
+					continue
+				}
+				reached[neighbor] = struct{}{}
+				next = append(next, neighbor)
+			}
+		}
+		frontier = next
+	}
+
+	focused := *d
+	focused.Packages = nil
+	for _, pkg := range d.Packages {
+		p := &Package{PackageRef: pkg.PackageRef}
+		for _, node := range pkg.Nodes {
+			if _, ok := reached[node.Ref()]; ok {
+				p.Nodes = append(p.Nodes, node)
+			}
+		}
+		if len(p.Nodes) == 0 {
+			continue
+		}
+		for _, edge := range pkg.Edges {
+			_, okFrom := reached[edge.From]
+			_, okTo := reached[edge.To]
+			if okFrom && okTo {
+				p.Edges = append(p.Edges, edge)
+			}
+		}
+		focused.Packages = append(focused.Packages, p)
+	}
+	return &focused
+}
+
+// neighbor は ref から e を direction の向きに辿った先のノードを返す。
+func (e *Edge) neighbor(ref NodeRef, direction FocusDirection) (NodeRef, bool) {
+	if e.From == ref && direction != FocusDirectionIncoming {
+		return e.To, true
+	}
+	if e.To == ref && direction != FocusDirectionOutgoing {
+		return e.From, true
+	}
+	return NodeRef{}, false
+}
@@ -0,0 +1,272 @@
+// Package model は解析結果と出力形式の間に置く、出力形式に依存しない図のモデルを提供する。
+//
+// 各 renderer は gocode の解析結果から Diagram を生成し、emitter が Diagram を PlantUML や Mermaid などの形式に変換する。
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	NodeKindStruct NodeKind = iota
+	NodeKindInterface
+	NodeKindDefinedType
+	NodeKindTypeAlias
+	// NodeKindUnderlyingType は defined type の基底となる名前を持たない型(func, map, slice など)を表す。
+	NodeKindUnderlyingType
+	// NodeKindPackageFunctions はパッケージレベルの関数をまとめたノードを表す。
+	NodeKindPackageFunctions
+	// NodeKindPackageVariables はパッケージレベルの変数をまとめたノードを表す。
+	NodeKindPackageVariables
+	// NodeKindConstraint は型パラメータの制約にのみ使える interface (型集合を持つ interface) を表す。
+	NodeKindConstraint
+)
+
+const (
+	// EdgeKindExtension は From が To の interface を実装していることを表す。
+	EdgeKindExtension EdgeKind = iota
+	// EdgeKindComposition は From が To を埋め込んでいることを表す。
+	EdgeKindComposition
+	// EdgeKindAggregation は From が To をフィールドとして保持していることを表す。
+	EdgeKindAggregation
+	// EdgeKindAlias は From が To を基底とする型、または To の別名であることを表す。
+	EdgeKindAlias
+	// EdgeKindImport は From のパッケージが To のパッケージを import していることを表す。
+	EdgeKindImport
+	// EdgeKindCreation は From の関数(コンストラクタ)が To を生成して返すことを表す。
+	EdgeKindCreation
+	// EdgeKindDependency は From のメソッドが引数または戻り値として To を使うことを表す。
+	EdgeKindDependency
+)
+
+const (
+	// ChangeStatusNone は変更がないこと、または差分の図でないことを表す。
+	ChangeStatusNone ChangeStatus = iota
+	// ChangeStatusAdded は基準とした図には存在せず、追加されたことを表す。
+	ChangeStatusAdded
+	// ChangeStatusRemoved は基準とした図にのみ存在し、削除されたことを表す。
+	ChangeStatusRemoved
+	// ChangeStatusChanged は基準とした図にも存在し、内容が変更されたことを表す。
+	ChangeStatusChanged
+)
+
+const (
+	// PackageOriginModule は解析したパッケージと同じモジュールのパッケージを表す。
+	PackageOriginModule PackageOrigin = iota
+	// PackageOriginStandard は標準ライブラリのパッケージを表す。
+	PackageOriginStandard
+	// PackageOriginThirdParty は go.mod で require した他のモジュールのパッケージを表す。
+	PackageOriginThirdParty
+)
+
+// PackageFunctionsNodeName は NodeKindPackageFunctions のノード名。
+// Go の識別子として使えない名前とし、型のノードと重複しないようにする。
+const PackageFunctionsNodeName = "package functions"
+
+// PackageVariablesNodeName は NodeKindPackageVariables のノード名。
+const PackageVariablesNodeName = "package variables"
+
+type (
+	NodeKind int
+
+	EdgeKind int
+
+	// ChangeStatus は差分の図において、基準とした図からの変更の種類を表す。
+	ChangeStatus int
+
+	// PackageOrigin はパッケージの提供元を表す。
+	PackageOrigin int
+
+	// Diagram は図全体を表す。
+	Diagram struct {
+		Title string
+		Notes string
+		Theme string
+		// ShortPackageNames はパッケージ名が重複しない限り、インポートパスの代わりにパッケージ名で表示することを表す。
+		ShortPackageNames bool
+		Packages          []*Package
+	}
+
+	// PackageRef はパッケージを識別する情報を表す。
+	PackageRef struct {
+		Path string
+		Name string
+	}
+
+	// Package はパッケージと、パッケージに所属するノードとパッケージを起点とするエッジを表す。
+	Package struct {
+		PackageRef
+		Nodes  []*Node
+		Edges  []*Edge
+		Status ChangeStatus
+		// Origin はパッケージ図においてパッケージの提供元を表す。クラス図では常に PackageOriginModule とする。
+		Origin PackageOrigin
+		// Heat はパッケージ図においてパッケージを色分けするための 0 から 1 の値を表す。 nil の場合は色分けしない。
+		Heat *float64
+	}
+
+	// NodeRef はノードを参照するための情報を表す。
+	// Name が空の場合はパッケージ自体を参照する。
+	NodeRef struct {
+		Package PackageRef
+		Name    string
+	}
+
+	// Node は図に描画される型を表す。
+	Node struct {
+		Kind    NodeKind
+		Package PackageRef
+		Name    string
+		// Label は Name とは異なる表示名を持つ場合に設定される。
+		Label string
+		// Underlying は builtin の型を基底とする defined type や type alias の場合に、その builtin の型名が設定される。
+		Underlying string
+		// TypeParams はジェネリックな型の場合に、型パラメータが宣言順に設定される。
+		TypeParams []*TypeParam
+		// TypeSet は NodeKindConstraint の場合に、型集合を表す union (~int | ~float64 など) が設定される。
+		TypeSet []string
+		// Constants は defined type の場合に、その型で宣言された exported な定数が宣言順に設定される。
+		Constants []*Constant
+		Fields    []*Field
+		Methods   []*Method
+		// Doc はドキュメントコメントの描画が指定された場合に、型のドキュメントコメントが設定される。
+		Doc    string
+		Status ChangeStatus
+	}
+
+	// Edge はノード間の関連を表す。
+	Edge struct {
+		Kind EdgeKind
+		From NodeRef
+		To   NodeRef
+		// Label は関連に添える文字列を表す。 EdgeKindCreation の場合は関数名が、
+		// EdgeKindAggregation の場合はフィールド名(インスタンス化された型の場合は型引数を含めた型名を続ける)が設定される。
+		Label string
+		// FromMultiplicity と ToMultiplicity は EdgeKindAggregation と EdgeKindComposition の場合に、
+		// From と To の側の多重度("1", "0..1", "*")が設定される。多重度を表せない場合は空とする。
+		FromMultiplicity string
+		ToMultiplicity   string
+		Status           ChangeStatus
+		// Violation は import の循環に含まれる、または規則に違反する関連であることを表す。
+		Violation bool
+	}
+
+	// TypeParam は型パラメータと、その制約を表す。
+	TypeParam struct {
+		Name       string
+		Constraint string
+	}
+
+	// Constant は定数と、その値を Go の定数式として表した文字列を表す。
+	Constant struct {
+		Name   string
+		Value  string
+		Status ChangeStatus
+	}
+
+	Field struct {
+		Name     string
+		Type     string
+		Exported bool
+		Embedded bool
+		// Tag は struct tag の描画が指定された場合に、描画する struct tag (json:"id" など) が設定される。
+		Tag string
+		// Doc はドキュメントコメントの描画が指定された場合に、フィールドのドキュメントコメントが設定される。
+		Doc    string
+		Status ChangeStatus
+	}
+
+	Method struct {
+		Name     string
+		Exported bool
+		// TypeParams はジェネリックな関数の場合に、型パラメータが宣言順に設定される。
+		TypeParams []*TypeParam
+		Params     []*Param
+		Results    []*Result
+		// Doc はドキュメントコメントの描画が指定された場合に、メソッドのドキュメントコメントが設定される。
+		Doc    string
+		Status ChangeStatus
+	}
+
+	Param struct {
+		Name string
+		Type string
+	}
+
+	Result struct {
+		Name string
+		Type string
+	}
+)
+
+func (n *Node) Ref() NodeRef {
+	return NodeRef{Package: n.Package, Name: n.Name}
+}
+
+// DisplayName は Label が設定されていれば Label を、そうでなければ Name に型パラメータを付与して返す。
+func (n *Node) DisplayName() string {
+	if n.Label != "" {
+		return n.Label
+	}
+	return withTypeParams(n.Name, n.TypeParams)
+}
+
+// DisplayName は Name に型パラメータを付与して返す。
+func (m *Method) DisplayName() string {
+	return withTypeParams(m.Name, m.TypeParams)
+}
+
+// withTypeParams は name に型パラメータを制約と合わせて付与する。型パラメータが無い場合は name をそのまま返す。
+func withTypeParams(name string, typeParams []*TypeParam) string {
+	if len(typeParams) == 0 {
+		return name
+	}
+	params := make([]string, 0, len(typeParams))
+	for _, tp := range typeParams {
+		params = append(params, fmt.Sprintf("%s %s", tp.Name, tp.Constraint))
+	}
+	return fmt.Sprintf("%s[%s]", name, strings.Join(params, ", "))
+}
+
+func (p *Package) Ref() NodeRef {
+	return NodeRef{Package: p.PackageRef}
+}
+
+// Nodes は全てのパッケージのノードを返す。
+func (d *Diagram) Nodes() []*Node {
+	var nodes []*Node
+	for _, p := range d.Packages {
+		nodes = append(nodes, p.Nodes...)
+	}
+	return nodes
+}
+
+// Edges は全てのパッケージのエッジを返す。
+func (d *Diagram) Edges() []*Edge {
+	var edges []*Edge
+	for _, p := range d.Packages {
+		edges = append(edges, p.Edges...)
+	}
+	return edges
+}
+
+// HasChanges は d が差分の図であり、一つでも変更を含むかを判定する。
+func (d *Diagram) HasChanges() bool {
+	for _, p := range d.Packages {
+		if p.Status != ChangeStatusNone {
+			return true
+		}
+		for _, node := range p.Nodes {
+			if node.Status != ChangeStatusNone {
+				return true
+			}
+		}
+		for _, edge := range p.Edges {
+			if edge.Status != ChangeStatusNone {
+				return true
+			}
+		}
+	}
+	return false
+}
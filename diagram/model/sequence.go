@@ -0,0 +1,55 @@
+package model
+
+const (
+	// ParticipantKindType はメソッドのレシーバの型を表す参加者。
+	ParticipantKindType ParticipantKind = iota
+	// ParticipantKindInterface は interface のメソッドの呼び出し先を表す参加者。実装は静的に定まらないため、呼び出し先を辿らない。
+	ParticipantKindInterface
+	// ParticipantKindPackage はパッケージレベルの関数をまとめたパッケージを表す参加者。
+	ParticipantKindPackage
+)
+
+type (
+	// ParticipantKind はシーケンス図の参加者の種類を表す。
+	ParticipantKind int
+
+	// Sequence は出力形式に依存しないシーケンス図のモデルを表す。
+	Sequence struct {
+		Title string
+		// Participants は最初にメッセージを受け取った順の参加者を表す。
+		Participants []*Participant
+		// Entry は図の外から起点の関数を呼び出すメッセージを表す。
+		Entry *Message
+	}
+
+	// Participant はシーケンス図の参加者を表す。
+	Participant struct {
+		// ID は図の中で参加者を一意に識別する名前を表す。
+		ID      string
+		Package PackageRef
+		// Name は型の名前を表す。 ParticipantKindPackage では空文字列とする。
+		Name string
+		Kind ParticipantKind
+	}
+
+	// Message は参加者の間の関数の呼び出しを表す。
+	Message struct {
+		// From は呼び出し元を表す。 nil の場合は図の外からの呼び出しとする。
+		From *Participant
+		To   *Participant
+		// Label は呼び出す関数またはメソッドの名前を表す。
+		Label string
+		// Async は go 文による呼び出しであることを表す。
+		Async bool
+		// Messages は呼び出された関数の中での呼び出しを呼び出す順に表す。
+		Messages []*Message
+	}
+)
+
+// DisplayName は参加者を図に表示する名前を返す。
+func (p *Participant) DisplayName() string {
+	if p.Kind == ParticipantKindPackage {
+		return p.Package.Name
+	}
+	return p.Package.Name + "." + p.Name
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 
 	"github.com/keisuke-m123/goanalyzer/gocode"
+	"github.com/keisuke-m123/godiagramgen/diagram/emitter"
+	"github.com/keisuke-m123/godiagramgen/diagram/metrics"
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
 	"github.com/spf13/afero"
 )
 
@@ -11,25 +14,97 @@ type (
 	Diagram struct {
 		renderer *renderer
 	}
+
+	// Options はパッケージ図の生成方法を表す。
+	Options struct {
+		Theme     string
+		Recursive bool
+		// Depth が 0 より大きい場合、解析したパッケージに共通するパスから Depth 個より深いセグメントを持つパッケージを、
+		// Depth 個のセグメントの祖先のパッケージにまとめ、 import もまとめた祖先の間の関係とする。
+		Depth int
+		// RenderExternalPackages は解析したパッケージ以外の import したパッケージ(標準ライブラリ、他のモジュールなど)も出力することを表す。
+		RenderExternalPackages bool
+		// HideStdlib は RenderExternalPackages の場合でも標準ライブラリのパッケージを出力しないことを表す。
+		HideStdlib bool
+		// HideThirdParty は RenderExternalPackages の場合でも他のモジュールのパッケージを出力しないことを表す。
+		HideThirdParty bool
+		// CollapseThirdParty は他のモジュールのパッケージを go.mod で require したモジュールのパスにまとめることを表す。
+		CollapseThirdParty bool
+		// ColorBy が空でない場合、同じモジュールのパッケージをそのメトリクスの値で色分けする。
+		ColorBy metrics.Metric
+	}
 )
 
+// NewDiagram は directoryPaths 配下の全てのパッケージを再帰的に読み込み、パッケージ図を生成する。
 func NewDiagram(directoryPaths []string, ignoreDirectories []string, theme string) (*Diagram, error) {
+	return NewDiagramWithOption(directoryPaths, ignoreDirectories, Options{Theme: theme, Recursive: true})
+}
+
+func NewDiagramWithOption(directoryPaths []string, ignoreDirectories []string, options Options) (*Diagram, error) {
+	if options.Depth < 0 {
+		return nil, fmt.Errorf("depth must not be negative: %d", options.Depth)
+	}
 	loadOptions := &gocode.LoadOptions{
 		Directories:        directoryPaths,
 		IgnoredDirectories: ignoreDirectories,
-		Recursive:          true,
+		Recursive:          options.Recursive,
 		FileSystem:         afero.NewOsFs(),
 	}
 	relations, err := gocode.LoadRelations(loadOptions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load relations: %w", err)
 	}
+	var m *module
+	if len(directoryPaths) > 0 {
+		if m, err = loadModule(directoryPaths[0]); err != nil {
+			return nil, fmt.Errorf("failed to load module: %w", err)
+		}
+	} else {
+		m = &module{}
+	}
 
 	return &Diagram{
-		renderer: newRenderer(theme, relations.PackageGraph()),
+		renderer: newRenderer(options, m, relations),
 	}, nil
 }
 
 func (d *Diagram) Render() string {
-	return d.renderer.render()
+	return d.RenderWith(emitter.NewPlantUMLPackageEmitter())
+}
+
+func (d *Diagram) RenderDOT() string {
+	return d.RenderWith(emitter.NewDOTPackageEmitter())
+}
+
+// Model は出力形式に依存しない図のモデルを返す。
+func (d *Diagram) Model() *model.Diagram {
+	m := d.renderer.build()
+	if d.renderer.options.ColorBy != "" {
+		metrics.Heat(m, metrics.Compute(m, d.renderer.collapsedTypeCounts()), d.renderer.options.ColorBy)
+	}
+	return m
+}
+
+// DependencyModel は Options の RenderExternalPackages, HideStdlib, HideThirdParty, CollapseThirdParty によらず、
+// 標準ライブラリと他のモジュールのパッケージへの import を全て含めた依存関係のモデルを返す。 Options.Depth によるまとめは適用する。
+//
+// 図に出力しないパッケージへの import も import の規則と循環の検証の対象とするため、検証にはこのモデルを使う。
+func (d *Diagram) DependencyModel() *model.Diagram {
+	return d.renderer.buildDependencies()
+}
+
+// DisplayPath は DependencyModel のパッケージのパスを、 Model で出力するパッケージのパスに変換する。
+func (d *Diagram) DisplayPath(pkgPath string) string {
+	return d.renderer.displayPath(pkgPath)
+}
+
+// Metrics は図のモデルに含まれる同じモジュールのパッケージ毎のメトリクスを返す。
+// Options.Depth でまとめたパッケージは、まとめた祖先のパッケージとして計算する。
+func (d *Diagram) Metrics() []*metrics.Package {
+	return metrics.Compute(d.renderer.build(), d.renderer.collapsedTypeCounts())
+}
+
+// RenderWith は図のモデルを e で変換して返す。
+func (d *Diagram) RenderWith(e emitter.Emitter) string {
+	return e.Emit(d.Model())
 }
@@ -7,6 +7,7 @@ import (
 	"runtime"
 	"testing"
 
+	"github.com/keisuke-m123/godiagramgen/diagram/metrics"
 	"github.com/keisuke-m123/godiagramgen/testutil"
 )
 
@@ -15,21 +16,37 @@ func TestClassDiagram_Render(t *testing.T) {
 		name              string
 		directories       []string
 		ignoreDirectories []string
-		theme             string
+		options           Options
 		wantFilePath      string
 	}{
 		{
 			name:              "GoDiagramGenAll",
 			directories:       []string{projectRootPath()},
 			ignoreDirectories: []string{path.Join(projectRootPath(), "testingsupport")},
-			theme:             "reddress-darkorange",
+			options:           Options{Theme: "reddress-darkorange", Recursive: true},
 			wantFilePath:      "../../package-diagram.puml",
 		},
+		{
+			name:         "TestingSupportWithDepth",
+			directories:  []string{path.Join(projectRootPath(), "testingsupport")},
+			options:      Options{Recursive: true, Depth: 1},
+			wantFilePath: "../../testingsupport/testingsupport-package-depth.puml",
+		},
+		{
+			name:        "ImportsHideStdlib",
+			directories: []string{path.Join(projectRootPath(), "testingsupport/imports")},
+			options: Options{
+				Recursive:              true,
+				RenderExternalPackages: true,
+				HideStdlib:             true,
+			},
+			wantFilePath: "../../testingsupport/imports-package-hide-stdlib.puml",
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			d, err := NewDiagram(test.directories, test.ignoreDirectories, test.theme)
+			d, err := NewDiagramWithOption(test.directories, test.ignoreDirectories, test.options)
 			if err != nil {
 				t.Fatalf("failed newDiagram: %s", err)
 			}
@@ -54,6 +71,93 @@ func TestClassDiagram_Render(t *testing.T) {
 	}
 }
 
+func TestPackageDiagram_RenderDOT(t *testing.T) {
+	tests := []struct {
+		name         string
+		directories  []string
+		options      Options
+		wantFilePath string
+	}{
+		{
+			name:         "TestingSupport",
+			directories:  []string{path.Join(projectRootPath(), "testingsupport")},
+			options:      Options{Recursive: true},
+			wantFilePath: "../../testingsupport/testingsupport-package.dot",
+		},
+		{
+			name:        "ImportsCollapseThirdParty",
+			directories: []string{path.Join(projectRootPath(), "testingsupport/imports")},
+			options: Options{
+				Recursive:              true,
+				RenderExternalPackages: true,
+				CollapseThirdParty:     true,
+			},
+			wantFilePath: "../../testingsupport/imports-package.dot",
+		},
+		{
+			name:         "LayersColorByInstability",
+			directories:  []string{path.Join(projectRootPath(), "testingsupport/metrics")},
+			options:      Options{Recursive: true, ColorBy: metrics.MetricInstability},
+			wantFilePath: "../../testingsupport/metrics-package-instability.dot",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d, err := NewDiagramWithOption(test.directories, nil, test.options)
+			if err != nil {
+				t.Fatalf("failed newDiagram: %s", err)
+			}
+
+			fileBytes, err := ioutil.ReadFile(test.wantFilePath)
+			if err != nil {
+				t.Fatalf("failed open want file %s: %s", test.wantFilePath, err)
+			}
+
+			got := d.RenderDOT()
+			want := string(fileBytes)
+
+			if got != want {
+				t.Errorf(
+					"failed render: want %s\n\ngot %s\n\ndiff: %s",
+					want,
+					got,
+					testutil.Diff(t, want, got),
+				)
+			}
+		})
+	}
+}
+
+func TestPackageDiagram_Metrics(t *testing.T) {
+	d, err := NewDiagramWithOption(
+		[]string{path.Join(projectRootPath(), "testingsupport/metrics")},
+		nil,
+		Options{Recursive: true},
+	)
+	if err != nil {
+		t.Fatalf("failed newDiagram: %s", err)
+	}
+
+	wantFilePath := "../../testingsupport/metrics.csv"
+	fileBytes, err := ioutil.ReadFile(wantFilePath)
+	if err != nil {
+		t.Fatalf("failed open want file %s: %s", wantFilePath, err)
+	}
+
+	got := metrics.RenderCSV(d.Metrics())
+	want := string(fileBytes)
+
+	if got != want {
+		t.Errorf(
+			"failed render: want %s\n\ngot %s\n\ndiff: %s",
+			want,
+			got,
+			testutil.Diff(t, want, got),
+		)
+	}
+}
+
 func projectRootPath() string {
 	_, b, _, _ := runtime.Caller(0)
 	return filepath.Join(filepath.Dir(b), "../../")
@@ -0,0 +1,84 @@
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+	"golang.org/x/mod/modfile"
+)
+
+type (
+	// module は解析したディレクトリを含むモジュールの go.mod の情報を表す。
+	module struct {
+		path string
+		// requires は go.mod で require したモジュールのパス。
+		requires []string
+	}
+)
+
+// loadModule は dir から親ディレクトリへ go.mod を探して読み込む。 go.mod が見つからない場合は空の module を返す。
+func loadModule(dir string) (*module, error) {
+	for {
+		goMod := filepath.Join(dir, "go.mod")
+		data, err := os.ReadFile(goMod)
+		if err == nil {
+			f, err := modfile.Parse(goMod, data, nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", goMod, err)
+			}
+			m := &module{}
+			if f.Module != nil {
+				m.path = f.Module.Mod.Path
+			}
+			for _, r := range f.Require {
+				m.requires = append(m.requires, r.Mod.Path)
+			}
+			return m, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return &module{}, nil
+		}
+		dir = parent
+	}
+}
+
+// origin は pkgPath のパッケージの提供元を判定する。
+//
+// 最初のセグメントにドメインを表す . を含まないパッケージを標準ライブラリとし、
+// go.mod が見つからない場合は標準ライブラリ以外を同じモジュールのパッケージとする。
+func (m *module) origin(pkgPath string) model.PackageOrigin {
+	if !strings.Contains(strings.Split(pkgPath, "/")[0], ".") {
+		if m.path == "" || !hasPathPrefix(pkgPath, m.path) {
+			return model.PackageOriginStandard
+		}
+	}
+	if m.path == "" || hasPathPrefix(pkgPath, m.path) {
+		return model.PackageOriginModule
+	}
+	return model.PackageOriginThirdParty
+}
+
+// root は pkgPath を含む require したモジュールのパスを返す。該当するモジュールがない場合は pkgPath を返す。
+func (m *module) root(pkgPath string) string {
+	root := pkgPath
+	found := false
+	for _, r := range m.requires {
+		if hasPathPrefix(pkgPath, r) && (!found || len(r) > len(root)) {
+			root = r
+			found = true
+		}
+	}
+	return root
+}
+
+// hasPathPrefix は pkgPath が prefix 自体、または prefix 配下のパスであるかを判定する。
+func hasPathPrefix(pkgPath, prefix string) bool {
+	return pkgPath == prefix || strings.HasPrefix(pkgPath, prefix+"/")
+}
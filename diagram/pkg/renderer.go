@@ -1,65 +1,201 @@
 package pkg
 
 import (
+	"path"
+	"sort"
 	"strings"
 
 	"github.com/keisuke-m123/goanalyzer/gocode"
-	"github.com/keisuke-m123/godiagramgen/plantuml"
+	"github.com/keisuke-m123/godiagramgen/diagram/metrics"
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
 )
 
 type renderer struct {
-	theme    string
+	options  Options
+	module   *module
 	pkgGraph *gocode.PackageGraph
+	// externalGraph は options によらず外部パッケージへの import を含めた依存関係を表す。
+	externalGraph *gocode.PackageGraph
+	// pkgNames は出力するパッケージのパスとパッケージ名の対応。
+	pkgNames map[string]string
+	// typeCounts は解析したパッケージのパスと宣言された型の数の対応。
+	typeCounts map[string]metrics.TypeCounts
 }
 
-func newRenderer(theme string, pkgGraph *gocode.PackageGraph) *renderer {
+func newRenderer(options Options, m *module, relations *gocode.Relations) *renderer {
+	externalGraph := relations.PackageGraphWithExternalPackages()
+	pkgGraph := relations.PackageGraph()
+	if options.RenderExternalPackages {
+		pkgGraph = externalGraph
+	}
+	pkgNames := make(map[string]string)
+	typeCounts := make(map[string]metrics.TypeCounts)
+	for _, p := range relations.Packages().AsSlice() {
+		pkgNames[p.Summary().Path().String()] = p.Summary().Name().String()
+		typeCounts[p.Summary().Path().String()] = metrics.TypeCounts{
+			Interfaces: len(p.Detail().Interfaces()),
+			Structs:    len(p.Detail().Structs()),
+		}
+	}
+	for _, p := range externalGraph.SortedPackagePaths() {
+		for _, imSummary := range externalGraph.SortedImportPackagePaths(p) {
+			if _, ok := pkgNames[imSummary.Path().String()]; !ok {
+				pkgNames[imSummary.Path().String()] = imSummary.Name().String()
+			}
+		}
+	}
 	return &renderer{
-		theme:    theme,
-		pkgGraph: pkgGraph,
+		options:       options,
+		module:        m,
+		pkgGraph:      pkgGraph,
+		externalGraph: externalGraph,
+		pkgNames:      pkgNames,
+		typeCounts:    typeCounts,
 	}
 }
 
-func (r *renderer) render() string {
-	elements := plantuml.NewElementStore()
-	if r.theme != "" {
-		elements.Add(plantuml.Theme(r.theme))
+// buildDependencies は表示のオプションによらず、外部パッケージへの import を全て含めた依存関係のモデルを生成する。
+// Options.Depth によるまとめのみ適用する。
+func (r *renderer) buildDependencies() *model.Diagram {
+	dr := *r
+	dr.pkgGraph = r.externalGraph
+	dr.options = Options{Theme: r.options.Theme, Depth: r.options.Depth, RenderExternalPackages: true}
+	return dr.build()
+}
+
+// displayPath は buildDependencies のモデルのパッケージのパスを、 build のモデルで出力するパッケージのパスに変換する。
+func (r *renderer) displayPath(pkgPath string) string {
+	return r.packageRef(pkgPath, commonSegments(r.pkgGraph.SortedPackagePaths())).Path
+}
+
+// collapsedTypeCounts はまとめた祖先のパッケージ毎に型の数を合計する。
+func (r *renderer) collapsedTypeCounts() map[string]metrics.TypeCounts {
+	root := commonSegments(r.pkgGraph.SortedPackagePaths())
+	counts := make(map[string]metrics.TypeCounts)
+	for path, c := range r.typeCounts {
+		ref := r.packageRef(path, root)
+		sum := counts[ref.Path]
+		sum.Interfaces += c.Interfaces
+		sum.Structs += c.Structs
+		counts[ref.Path] = sum
 	}
-	for _, path := range r.pkgGraph.SortedPackagePaths() {
-		elements.Add(r.buildNamespace(path))
-		for _, imPath := range r.pkgGraph.SortedImportPackagePaths(path) {
-			elements.Add(r.buildNamespace(imPath.Path()))
+	return counts
+}
+
+// build はパッケージ間の依存関係から出力形式に依存しない図のモデルを生成する。
+//
+// まとめたパッケージ同士の import は関連として出力しない。
+func (r *renderer) build() *model.Diagram {
+	paths := r.pkgGraph.SortedPackagePaths()
+	root := commonSegments(paths)
+
+	packages := make(map[string]*model.Package)
+	addPackage := func(ref model.PackageRef) *model.Package {
+		p, ok := packages[ref.Path]
+		if !ok {
+			p = &model.Package{PackageRef: ref, Origin: r.module.origin(ref.Path)}
+			packages[ref.Path] = p
 		}
+		return p
 	}
-	for _, path := range r.pkgGraph.SortedPackagePaths() {
-		for _, imPath := range r.pkgGraph.SortedImportPackagePaths(path) {
-			elements.Add(plantuml.Relation(
-				plantuml.NewRelationTarget(r.relationTargetName(path)),
-				plantuml.NewRelationTarget(r.relationTargetName(imPath.Path())),
-				plantuml.RelationTypeArrow,
-			))
+	importSets := make(map[string]map[string]struct{})
+	for _, p := range paths {
+		from := addPackage(r.packageRef(p.String(), root))
+		if _, ok := importSets[from.Path]; !ok {
+			importSets[from.Path] = make(map[string]struct{})
+		}
+		for _, imSummary := range r.pkgGraph.SortedImportPackagePaths(p) {
+			if r.isHidden(imSummary.Path().String()) {
+				continue
+			}
+			to := r.packageRef(imSummary.Path().String(), root)
+			if to.Path == from.Path {
+				continue
+			}
+			if _, ok := importSets[from.Path][to.Path]; ok {
+				continue
+			}
+			importSets[from.Path][to.Path] = struct{}{}
+			from.Edges = append(from.Edges, &model.Edge{
+				Kind: model.EdgeKindImport,
+				From: from.Ref(),
+				To:   model.NodeRef{Package: to},
+			})
+			if r.options.RenderExternalPackages {
+				addPackage(to)
+			}
 		}
 	}
-	return plantuml.PlantUML(elements.AsSlice()...).String()
+
+	d := &model.Diagram{Theme: r.options.Theme}
+	for _, p := range packages {
+		sort.Slice(p.Edges, func(i, j int) bool {
+			return strings.Compare(p.Edges[i].To.Package.Path, p.Edges[j].To.Package.Path) < 0
+		})
+		d.Packages = append(d.Packages, p)
+	}
+	sort.Slice(d.Packages, func(i, j int) bool {
+		return strings.Compare(d.Packages[i].Path, d.Packages[j].Path) < 0
+	})
+	return d
 }
 
-func (r *renderer) buildNamespace(pkgPath gocode.PackagePath) plantuml.Element {
-	ps := strings.Split(r.namespacePath(pkgPath), "/")
-	var ns plantuml.Element
-	for i := len(ps) - 1; i >= 0; i-- {
-		if ns == nil {
-			ns = plantuml.Namespace(ps[i])
-		} else {
-			ns = plantuml.Namespace(ps[i], ns)
-		}
+func (r *renderer) isHidden(pkgPath string) bool {
+	switch r.module.origin(pkgPath) {
+	case model.PackageOriginStandard:
+		return r.options.HideStdlib
+	case model.PackageOriginThirdParty:
+		return r.options.HideThirdParty
+	default:
+		return false
+	}
+}
+
+// packageRef は pkgPath のパッケージを、オプションに従ってまとめた祖先のパッケージとして返す。
+//
+// root 配下のパッケージは root から r.options.Depth 個のセグメントまでに切り詰め、
+// 他のモジュールのパッケージは r.options.CollapseThirdParty の場合に require したモジュールのパスに切り詰める。
+// 切り詰めたパスにパッケージが存在しない場合は、パスの最後のセグメントをパッケージ名とする。
+func (r *renderer) packageRef(pkgPath string, root []string) model.PackageRef {
+	segments := strings.Split(pkgPath, "/")
+	switch {
+	case r.options.Depth > 0 && r.isUnder(pkgPath, root) && len(segments) > len(root)+r.options.Depth:
+		pkgPath = strings.Join(segments[:len(root)+r.options.Depth], "/")
+	case r.options.CollapseThirdParty && r.module.origin(pkgPath) == model.PackageOriginThirdParty:
+		pkgPath = r.module.root(pkgPath)
 	}
-	return ns
+	name, ok := r.pkgNames[pkgPath]
+	if !ok {
+		name = path.Base(pkgPath)
+	}
+	return model.PackageRef{Path: pkgPath, Name: name}
 }
 
-func (r *renderer) namespacePath(pkgPath gocode.PackagePath) string {
-	return strings.ReplaceAll(pkgPath.String(), ".", "")
+// isUnder は pkgPath が root 配下の同じモジュールのパッケージであるかを判定する。
+func (r *renderer) isUnder(pkgPath string, root []string) bool {
+	if r.module.origin(pkgPath) != model.PackageOriginModule {
+		return false
+	}
+	return len(root) == 0 || hasPathPrefix(pkgPath, strings.Join(root, "/"))
 }
 
-func (r *renderer) relationTargetName(pkgPath gocode.PackagePath) string {
-	t := r.namespacePath(pkgPath)
-	return strings.ReplaceAll(t, "/", ".")
+// commonSegments は全てのパスに共通する先頭のセグメントを返す。
+// パスが一つの場合も、パッケージ自体をまとめないよう最後のセグメントは含めない。
+func commonSegments(paths []gocode.PackagePath) []string {
+	if len(paths) == 0 {
+		return nil
+	}
+	common := strings.Split(paths[0].String(), "/")
+	if len(paths) == 1 {
+		return common[:len(common)-1]
+	}
+	for _, p := range paths[1:] {
+		segments := strings.Split(p.String(), "/")
+		n := 0
+		for n < len(common) && n < len(segments) && common[n] == segments[n] {
+			n++
+		}
+		common = common[:n]
+	}
+	return common
 }
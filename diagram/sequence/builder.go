@@ -0,0 +1,224 @@
+package sequence
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+	"unicode"
+
+	"github.com/keisuke-m123/godiagramgen/diagram/filter"
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+	"golang.org/x/tools/go/ssa"
+)
+
+type (
+	// builder は起点の関数から呼び出しを辿り、シーケンス図のモデルを生成する。
+	builder struct {
+		program         *program
+		depth           int
+		includePackages filter.Patterns
+		excludePackages filter.Patterns
+		sequence        *model.Sequence
+		// participants は参加者をパッケージのパスと型の名前で保持する。
+		participants map[participantKey]*model.Participant
+		// ids は参加者に割り当てた ID を保持する。
+		ids map[string]struct{}
+	}
+
+	participantKey struct {
+		path string
+		name string
+	}
+
+	// callee は呼び出し先の参加者とメッセージの名前を表す。
+	callee struct {
+		participant *model.Participant
+		label       string
+	}
+)
+
+func newBuilder(p *program, depth int, includePackages, excludePackages filter.Patterns) *builder {
+	return &builder{
+		program:         p,
+		depth:           depth,
+		includePackages: includePackages,
+		excludePackages: excludePackages,
+		sequence:        &model.Sequence{},
+		participants:    make(map[participantKey]*model.Participant),
+		ids:             make(map[string]struct{}),
+	}
+}
+
+func (b *builder) build(entry *ssa.Function) (*model.Sequence, error) {
+	c, ok := b.resolveFunction(entry)
+	if !ok {
+		return nil, fmt.Errorf("entry function %s is excluded", entry)
+	}
+	message := &model.Message{To: c.participant, Label: c.label}
+	message.Messages = b.calls(entry, c, 1, map[*ssa.Function]struct{}{entry: {}})
+	b.sequence.Entry = message
+	return b.sequence, nil
+}
+
+// calls は fn の中での呼び出しを出現順にメッセージに変換する。 current は fn を表す呼び出し先とする。
+//
+// depth は fn の中での呼び出しのネストの深さとし、深さの上限を超える場合は辿らない。
+// stack は辿っている途中の関数を表し、再帰呼び出しはメッセージのみとして辿らない。
+// defer 文による呼び出しは、関数の終わりに defer 文と逆の順で呼び出すものとする。
+func (b *builder) calls(fn *ssa.Function, current callee, depth int, stack map[*ssa.Function]struct{}) []*model.Message {
+	if b.depth > 0 && depth > b.depth {
+		return nil
+	}
+	var messages, deferred []*model.Message
+	for _, block := range fn.Blocks {
+		for _, instr := range block.Instrs {
+			call, ok := instr.(ssa.CallInstruction)
+			if !ok {
+				continue
+			}
+			_, async := instr.(*ssa.Go)
+			called := b.call(fn, call.Common(), async, current, depth, stack)
+			if _, ok := instr.(*ssa.Defer); ok {
+				deferred = append(called, deferred...)
+				continue
+			}
+			messages = append(messages, called...)
+		}
+	}
+	return append(messages, deferred...)
+}
+
+func (b *builder) call(
+	caller *ssa.Function,
+	common *ssa.CallCommon,
+	async bool,
+	current callee,
+	depth int,
+	stack map[*ssa.Function]struct{},
+) []*model.Message {
+	if common.IsInvoke() {
+		to, ok := b.interfaceParticipant(common.Value.Type())
+		if !ok {
+			return nil
+		}
+		return []*model.Message{{From: current.participant, To: to, Label: label(common.Method.Name()), Async: async}}
+	}
+
+	fn := common.StaticCallee()
+	if fn == nil {
+		return nil
+	}
+	if _, ok := stack[fn]; ok && fn.Parent() != nil {
+		return nil
+	}
+	c, ok := b.resolveFunction(fn)
+	// 無名関数は呼び出し元の一部として辿る。
+	// ラッパーやジェネリクスの関数のインスタンスが同じ関数を呼び出す場合も、呼び出し元の一部として辿る。
+	if fn.Parent() != nil || (caller.Synthetic != "" && ok && c == current) {
+		stack[fn] = struct{}{}
+		defer delete(stack, fn)
+		return b.calls(fn, current, depth, stack)
+	}
+	if !ok {
+		return nil
+	}
+
+	message := &model.Message{From: current.participant, To: c.participant, Label: c.label, Async: async}
+	if _, ok := stack[fn]; !ok {
+		stack[fn] = struct{}{}
+		message.Messages = b.calls(fn, c, depth+1, stack)
+		delete(stack, fn)
+	}
+	return []*model.Message{message}
+}
+
+// resolveFunction は fn を宣言した型またはパッケージを参加者とする呼び出し先を返す。
+// 読み込んだパッケージ以外や、絞り込みの条件に一致しないパッケージで宣言された関数の場合は false を返す。
+func (b *builder) resolveFunction(fn *ssa.Function) (callee, bool) {
+	obj, ok := fn.Object().(*types.Func)
+	if !ok || !b.keepPackage(obj.Pkg()) {
+		return callee{}, false
+	}
+	recv := obj.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return callee{
+			participant: b.participant(obj.Pkg(), "", model.ParticipantKindPackage),
+			label:       label(obj.Name()),
+		}, true
+	}
+	t := recv.Type()
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok {
+		return callee{}, false
+	}
+	kind := model.ParticipantKindType
+	if types.IsInterface(named) {
+		kind = model.ParticipantKindInterface
+	}
+	return callee{
+		participant: b.participant(obj.Pkg(), named.Obj().Name(), kind),
+		label:       label(obj.Name()),
+	}, true
+}
+
+// interfaceParticipant は t の interface を参加者として返す。名前を持たない interface の場合は false を返す。
+func (b *builder) interfaceParticipant(t types.Type) (*model.Participant, bool) {
+	named, ok := t.(*types.Named)
+	if !ok || !b.keepPackage(named.Obj().Pkg()) {
+		return nil, false
+	}
+	return b.participant(named.Obj().Pkg(), named.Obj().Name(), model.ParticipantKindInterface), true
+}
+
+func (b *builder) keepPackage(pkg *types.Package) bool {
+	if !b.program.contains(pkg) {
+		return false
+	}
+	if len(b.includePackages) > 0 && !b.includePackages.MatchAny(pkg.Path()) {
+		return false
+	}
+	return !b.excludePackages.MatchAny(pkg.Path())
+}
+
+// participant は pkg の name の型(空文字列の場合はパッケージ)を表す参加者を返す。初めて参照された場合は参加者に追加する。
+func (b *builder) participant(pkg *types.Package, name string, kind model.ParticipantKind) *model.Participant {
+	key := participantKey{path: pkg.Path(), name: name}
+	if p, ok := b.participants[key]; ok {
+		return p
+	}
+	p := &model.Participant{
+		Package: model.PackageRef{Path: pkg.Path(), Name: pkg.Name()},
+		Name:    name,
+		Kind:    kind,
+	}
+	p.ID = b.newID(p.DisplayName())
+	b.participants[key] = p
+	b.sequence.Participants = append(b.sequence.Participants, p)
+	return p
+}
+
+// newID は displayName の英数字以外を _ に置き換えた、他の参加者と重複しない ID を返す。
+func (b *builder) newID(displayName string) string {
+	base := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, displayName)
+	id := base
+	for i := 2; ; i++ {
+		if _, ok := b.ids[id]; !ok {
+			break
+		}
+		id = fmt.Sprintf("%s_%d", base, i)
+	}
+	b.ids[id] = struct{}{}
+	return id
+}
+
+func label(name string) string {
+	return name + "()"
+}
@@ -0,0 +1,72 @@
+// Package sequence は起点の関数から静的に辿れる呼び出しをシーケンス図として生成する。
+//
+// golang.org/x/tools/go/ssa で読み込んだパッケージを SSA 形式に変換し、関数の中の呼び出しを出現順に辿る。
+// 呼び出し先が静的に定まる関数とメソッドのみを辿り、 interface のメソッドの呼び出しは interface へのメッセージとして辿らない。
+package sequence
+
+import (
+	"fmt"
+
+	"github.com/keisuke-m123/godiagramgen/diagram/emitter"
+	"github.com/keisuke-m123/godiagramgen/diagram/filter"
+	"github.com/keisuke-m123/godiagramgen/diagram/model"
+)
+
+type (
+	Diagram struct {
+		sequence *model.Sequence
+	}
+
+	// Options はシーケンス図の生成方法を表す。
+	Options struct {
+		// Entry は起点の関数を pkg.Func または pkg.Type.Method の形式で表す。 pkg にはインポートパスとパッケージ名のどちらも指定できる。
+		Entry     string
+		Recursive bool
+		// Depth は起点から辿る呼び出しのネストの深さを表す。 0 の場合は制限しない。
+		Depth int
+		// IncludePackages と ExcludePackages はカンマ区切りのインポートパスのパターンで、呼び出しを辿るパッケージを絞り込む。
+		IncludePackages string
+		ExcludePackages string
+	}
+)
+
+// NewDiagramWithOption は directoryPaths のパッケージを読み込み、 options.Entry を起点とするシーケンス図を生成する。
+//
+// 呼び出しは directoryPaths のパッケージで宣言された関数とメソッドのみを辿る。
+func NewDiagramWithOption(directoryPaths []string, ignoreDirectories []string, options Options) (*Diagram, error) {
+	if options.Depth < 0 {
+		return nil, fmt.Errorf("depth must not be negative: %d", options.Depth)
+	}
+	includePackages, err := filter.ParsePatterns(options.IncludePackages)
+	if err != nil {
+		return nil, err
+	}
+	excludePackages, err := filter.ParsePatterns(options.ExcludePackages)
+	if err != nil {
+		return nil, err
+	}
+	p, err := load(directoryPaths, ignoreDirectories, options.Recursive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+	entry, err := p.resolveEntry(options.Entry)
+	if err != nil {
+		return nil, fmt.Errorf("invalid entry: %w", err)
+	}
+
+	s, err := newBuilder(p, options.Depth, includePackages, excludePackages).build(entry)
+	if err != nil {
+		return nil, fmt.Errorf("invalid entry: %w", err)
+	}
+	s.Title = options.Entry
+	return &Diagram{sequence: s}, nil
+}
+
+func (d *Diagram) Render() string {
+	return emitter.NewPlantUMLSequenceEmitter().Emit(d.sequence)
+}
+
+// Model は出力形式に依存しない図のモデルを返す。
+func (d *Diagram) Model() *model.Sequence {
+	return d.sequence
+}
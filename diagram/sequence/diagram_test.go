@@ -0,0 +1,96 @@
+package sequence
+
+import (
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/keisuke-m123/godiagramgen/testutil"
+)
+
+func TestSequenceDiagram_Render(t *testing.T) {
+	tests := []struct {
+		name         string
+		options      Options
+		wantFilePath string
+	}{
+		{
+			name:         "OrderHandlerHandle",
+			options:      Options{Entry: "sequence.OrderHandler.Handle", Recursive: true, Depth: 3},
+			wantFilePath: "../../testingsupport/sequence.puml",
+		},
+		{
+			name: "WithDepthAndExcludePackages",
+			options: Options{
+				Entry:           "github.com/keisuke-m123/godiagramgen/testingsupport/sequence.OrderHandler.Handle",
+				Recursive:       true,
+				Depth:           1,
+				ExcludePackages: "**/audit",
+			},
+			wantFilePath: "../../testingsupport/sequence-depth.puml",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d, err := NewDiagramWithOption(
+				[]string{path.Join(projectRootPath(), "testingsupport/sequence")},
+				nil,
+				test.options,
+			)
+			if err != nil {
+				t.Fatalf("failed newDiagram: %s", err)
+			}
+
+			fileBytes, err := ioutil.ReadFile(test.wantFilePath)
+			if err != nil {
+				t.Fatalf("failed open want file %s: %s", test.wantFilePath, err)
+			}
+
+			got := d.Render()
+			want := string(fileBytes)
+
+			if got != want {
+				t.Errorf(
+					"failed render: want %s\n\ngot %s\n\ndiff: %s",
+					want,
+					got,
+					testutil.Diff(t, want, got),
+				)
+			}
+		})
+	}
+}
+
+func TestNewDiagramWithOption_InvalidEntry(t *testing.T) {
+	tests := []struct {
+		name    string
+		entry   string
+		wantErr string
+	}{
+		{name: "Malformed", entry: "Handle", wantErr: "entry must be specified as pkg.Func or pkg.Type.Method"},
+		{name: "NotFound", entry: "sequence.OrderHandler.Missing", wantErr: "entry function not found"},
+		{name: "InterfaceMethod", entry: "sequence.OrderRepository.Save", wantErr: "entry must not be an interface method"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewDiagramWithOption(
+				[]string{path.Join(projectRootPath(), "testingsupport/sequence")},
+				nil,
+				Options{Entry: test.entry, Recursive: true},
+			)
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("failed error: want %q, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func projectRootPath() string {
+	_, b, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(b), "../../")
+}
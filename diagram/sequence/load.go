@@ -0,0 +1,151 @@
+package sequence
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/keisuke-m123/goanalyzer/gocode"
+	"github.com/keisuke-m123/godiagramgen/diagram/directory"
+	"github.com/spf13/afero"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+type (
+	// program は読み込んだパッケージの SSA 形式を保持する。
+	program struct {
+		prog *ssa.Program
+		// packages は読み込んだパッケージをインポートパスの順で保持する。呼び出しはこれらのパッケージの関数のみを辿る。
+		packages []*types.Package
+	}
+)
+
+// load は directoryPaths のパッケージ(recursive の場合は配下の全てのパッケージ)を読み込み、 SSA 形式に変換する。
+//
+// ディレクトリの探索方法は gocode.LoadRelations と同一とする。
+func load(directoryPaths []string, ignoreDirectories []string, recursive bool) (*program, error) {
+	if len(directoryPaths) == 0 {
+		return nil, errors.New("no directories specified")
+	}
+	dirs, err := directory.Collect(&gocode.LoadOptions{
+		FileSystem:         afero.NewOsFs(),
+		Directories:        directoryPaths,
+		IgnoredDirectories: ignoreDirectories,
+		Recursive:          recursive,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	loadConfig := &packages.Config{
+		Mode: packages.NeedName |
+			packages.NeedFiles |
+			packages.NeedCompiledGoFiles |
+			packages.NeedImports |
+			packages.NeedTypes |
+			packages.NeedTypesSizes |
+			packages.NeedSyntax |
+			packages.NeedTypesInfo,
+		Dir: directoryPaths[0],
+	}
+	loaded, err := packages.Load(loadConfig, dirs...)
+	if err != nil {
+		return nil, fmt.Errorf("load packages failed: %w", err)
+	}
+	var initial []*packages.Package
+	for _, pkg := range loaded {
+		// Go のファイルを含まないディレクトリは読み込まない。
+		if pkg.Types == nil || len(pkg.Syntax) == 0 {
+			continue
+		}
+		if len(pkg.Errors) > 0 {
+			return nil, fmt.Errorf("load package %s failed: %s", pkg.PkgPath, pkg.Errors[0])
+		}
+		initial = append(initial, pkg)
+	}
+
+	prog, ssaPackages := ssautil.Packages(initial, ssa.InstantiateGenerics)
+	prog.Build()
+
+	p := &program{prog: prog}
+	for _, pkg := range ssaPackages {
+		if pkg != nil {
+			p.packages = append(p.packages, pkg.Pkg)
+		}
+	}
+	sort.SliceStable(p.packages, func(i, j int) bool {
+		return strings.Compare(p.packages[i].Path(), p.packages[j].Path()) < 0
+	})
+	return p, nil
+}
+
+// contains は pkg が読み込んだパッケージかを判定する。
+func (p *program) contains(pkg *types.Package) bool {
+	if pkg == nil {
+		return false
+	}
+	for _, loaded := range p.packages {
+		if loaded.Path() == pkg.Path() {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveEntry は pkg.Func または pkg.Type.Method の形式で指定された関数を探す。
+//
+// pkg にはインポートパスとパッケージ名のどちらも指定できる。複数の関数に一致する場合はエラーとする。
+func (p *program) resolveEntry(entry string) (*ssa.Function, error) {
+	i := strings.LastIndex(entry, ".")
+	if i <= 0 || i == len(entry)-1 {
+		return nil, fmt.Errorf("entry must be specified as pkg.Func or pkg.Type.Method: %s", entry)
+	}
+	head, name := entry[:i], entry[i+1:]
+
+	var candidates []*types.Func
+	for _, pkg := range p.packages {
+		if matchPackage(pkg, head) {
+			if fn, ok := pkg.Scope().Lookup(name).(*types.Func); ok {
+				candidates = append(candidates, fn)
+			}
+		}
+		j := strings.LastIndex(head, ".")
+		if j <= 0 || !matchPackage(pkg, head[:j]) {
+			continue
+		}
+		tn, ok := pkg.Scope().Lookup(head[j+1:]).(*types.TypeName)
+		if !ok {
+			continue
+		}
+		obj, _, _ := types.LookupFieldOrMethod(tn.Type(), true, pkg, name)
+		if fn, ok := obj.(*types.Func); ok {
+			candidates = append(candidates, fn)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("entry function not found: %s", entry)
+	case 1:
+		fn := p.prog.FuncValue(candidates[0])
+		if fn == nil {
+			return nil, fmt.Errorf("entry must not be an interface method: %s", entry)
+		}
+		return fn, nil
+	default:
+		names := make([]string, 0, len(candidates))
+		for _, c := range candidates {
+			names = append(names, c.FullName())
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("entry function is ambiguous, specify one of: %s", strings.Join(names, ", "))
+	}
+}
+
+func matchPackage(pkg *types.Package, s string) bool {
+	return pkg.Path() == s || pkg.Name() == s
+}
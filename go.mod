@@ -1,20 +1,22 @@
 module github.com/keisuke-m123/godiagramgen
 
-go 1.17
+go 1.18
 
 require (
 	github.com/google/go-cmp v0.5.6
 	github.com/keisuke-m123/goanalyzer v0.0.0-20220329091650-492d1fd46160
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/spf13/afero v1.8.0
 	github.com/spf13/cobra v1.2.1
 	github.com/spf13/pflag v1.0.5
+	golang.org/x/mod v0.6.0-dev.0.20220419223038-86c51ed26bb4
+	golang.org/x/tools v0.1.12
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/inconshreveable/mousetrap v1.0.0 // indirect
-	golang.org/x/mod v0.5.1 // indirect
-	golang.org/x/sys v0.0.0-20211019181941-9d821ace8654 // indirect
+	golang.org/x/sys v0.0.0-20220722155257-8c9f86f7a55f // indirect
 	golang.org/x/text v0.3.7 // indirect
-	golang.org/x/tools v0.1.8 // indirect
 	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
 )
@@ -0,0 +1,38 @@
+package graphviz
+
+import "fmt"
+
+type (
+	attribute struct {
+		key string
+		val string
+	}
+
+	defaultAttributes struct {
+		target     string
+		attributes Attributes
+	}
+)
+
+func (a *attribute) Write(builder *LineStringBuilder, indent int) {
+	builder.WriteLineWithDepth(indent, fmt.Sprintf("%s=%s;", a.key, quote(a.val)))
+}
+
+// Attribute はグラフ(またはサブグラフ)自身の属性を表す。
+func Attribute(key, val string) Element {
+	return &attribute{key: key, val: val}
+}
+
+func (d *defaultAttributes) Write(builder *LineStringBuilder, indent int) {
+	builder.WriteLineWithDepth(indent, fmt.Sprintf("%s %s;", d.target, d.attributes.build()))
+}
+
+// NodeDefaults は以降に宣言される全てのノードに適用される属性を表す。
+func NodeDefaults(attributes Attributes) Element {
+	return &defaultAttributes{target: "node", attributes: attributes}
+}
+
+// EdgeDefaults は以降に宣言される全てのエッジに適用される属性を表す。
+func EdgeDefaults(attributes Attributes) Element {
+	return &defaultAttributes{target: "edge", attributes: attributes}
+}
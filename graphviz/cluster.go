@@ -0,0 +1,32 @@
+package graphviz
+
+import "fmt"
+
+type (
+	cluster struct {
+		id       string
+		label    string
+		elements []Element
+	}
+)
+
+func (c *cluster) Write(builder *LineStringBuilder, indent int) {
+	builder.WriteLineWithDepth(indent, fmt.Sprintf("subgraph %s {", quote("cluster_"+c.id)))
+	if c.label != "" {
+		builder.WriteLineWithDepth(indent+1, fmt.Sprintf("label=%s;", quote(c.label)))
+	}
+	for i := range c.elements {
+		c.elements[i].Write(builder, indent+1)
+	}
+	builder.WriteLineWithDepth(indent, "}")
+}
+
+// Cluster はノードをまとめて枠で囲むサブグラフを表す。
+// Graphvizはサブグラフ名が cluster から始まるものだけを枠として描画するため、 id には接頭辞 cluster_ を付与して出力する。
+func Cluster(id, label string, elements ...Element) Element {
+	return &cluster{
+		id:       id,
+		label:    label,
+		elements: elements,
+	}
+}
@@ -0,0 +1,31 @@
+package graphviz
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	edge struct {
+		from       string
+		to         string
+		attributes Attributes
+	}
+)
+
+func (e *edge) Write(builder *LineStringBuilder, indent int) {
+	builder.WriteLineWithDepth(indent, strings.TrimSpace(fmt.Sprintf(
+		"%s -> %s %s",
+		quote(e.from),
+		quote(e.to),
+		e.attributes.build(),
+	))+";")
+}
+
+func Edge(from, to string, attributes Attributes) Element {
+	return &edge{
+		from:       from,
+		to:         to,
+		attributes: attributes,
+	}
+}
@@ -0,0 +1,103 @@
+package graphviz
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	tab = "    "
+)
+
+// LineStringBuilder extends the strings.Builder and adds functionality to build a string with tabs and
+// adding new lines
+type LineStringBuilder struct {
+	strings.Builder
+}
+
+func newLineStringBuilder() *LineStringBuilder {
+	return &LineStringBuilder{}
+}
+
+// WriteLineWithDepth will write the given text with added tabs at the beginning into the string builder.
+func (lsb *LineStringBuilder) WriteLineWithDepth(depth int, str string) {
+	_, _ = lsb.WriteString(strings.Repeat(tab, depth))
+	_, _ = lsb.WriteString(str)
+	_, _ = lsb.WriteString("\n")
+}
+
+type Result struct {
+	builder *LineStringBuilder
+}
+
+func (r *Result) String() string {
+	return r.builder.String()
+}
+
+func newResult(builder *LineStringBuilder) *Result {
+	return &Result{builder: builder}
+}
+
+type Element interface {
+	Write(builder *LineStringBuilder, indent int)
+}
+
+type ElementStore struct {
+	elements []Element
+}
+
+func NewElementStore() *ElementStore {
+	return &ElementStore{}
+}
+
+func (e *ElementStore) Add(es ...Element) {
+	e.elements = append(e.elements, es...)
+}
+
+func (e *ElementStore) Merge(es *ElementStore) *ElementStore {
+	res := NewElementStore()
+	res.Add(append(e.elements, es.elements...)...)
+	return res
+}
+
+func (e *ElementStore) AsSlice() []Element {
+	return e.elements
+}
+
+// Attributes はDOT言語の属性リストを表す。出力時はキーの昇順に並べる。
+type Attributes map[string]string
+
+func (as Attributes) build() string {
+	var keys []string
+	for k, v := range as {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	sort.Strings(keys)
+
+	var ss []string
+	for _, k := range keys {
+		ss = append(ss, fmt.Sprintf("%s=%s", k, quote(as[k])))
+	}
+	return fmt.Sprintf("[%s]", strings.Join(ss, ", "))
+}
+
+func Digraph(name string, elements ...Element) *Result {
+	builder := newLineStringBuilder()
+	builder.WriteLineWithDepth(0, fmt.Sprintf("digraph %s {", quote(name)))
+	for i := range elements {
+		elements[i].Write(builder, 1)
+	}
+	builder.WriteLineWithDepth(0, "}")
+	return newResult(builder)
+}
+
+// quote はDOT言語のID文字列としてダブルクォートで囲んだ文字列を返す。
+func quote(s string) string {
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(s, `"`, `\"`))
+}
@@ -0,0 +1,24 @@
+package graphviz
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	node struct {
+		id         string
+		attributes Attributes
+	}
+)
+
+func (n *node) Write(builder *LineStringBuilder, indent int) {
+	builder.WriteLineWithDepth(indent, strings.TrimSpace(fmt.Sprintf("%s %s", quote(n.id), n.attributes.build()))+";")
+}
+
+func Node(id string, attributes Attributes) Element {
+	return &node{
+		id:         id,
+		attributes: attributes,
+	}
+}
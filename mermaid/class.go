@@ -0,0 +1,86 @@
+package mermaid
+
+import (
+	"fmt"
+	"regexp"
+)
+
+const (
+	AccessModifierPublic AccessModifier = iota
+	AccessModifierPrivate
+)
+
+type (
+	AccessModifier int
+
+	class struct {
+		id         string
+		label      string
+		annotation Annotation
+		elements   []Element
+	}
+
+	ClassOptions struct {
+		Label      string
+		Annotation Annotation
+	}
+
+	Annotation string
+)
+
+func (am AccessModifier) toString() string {
+	switch am {
+	case AccessModifierPublic:
+		return "+"
+	case AccessModifierPrivate:
+		return "-"
+	default:
+		return ""
+	}
+}
+
+var invalidClassIDChars = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+// ClassID は namespace と name からMermaidのクラスIDとして利用可能な文字列を生成する。
+//
+// MermaidのクラスIDはダイアグラム全体で一意である必要があるため、namespace を含めて生成する。
+func ClassID(namespace, name string) string {
+	if namespace == "" {
+		return invalidClassIDChars.ReplaceAllString(name, "_")
+	}
+	return invalidClassIDChars.ReplaceAllString(fmt.Sprintf("%s_%s", namespace, name), "_")
+}
+
+func Class(id string, elements ...Element) Element {
+	return ClassWithOption(id, ClassOptions{}, elements...)
+}
+
+func ClassWithOption(id string, options ClassOptions, elements ...Element) Element {
+	return &class{
+		id:         id,
+		label:      options.Label,
+		annotation: options.Annotation,
+		elements:   elements,
+	}
+}
+
+func (c *class) Write(builder *LineStringBuilder, indent int) {
+	var label string
+	if c.label != "" {
+		label = fmt.Sprintf(`["%s"]`, escape(c.label))
+	}
+
+	if c.annotation == "" && len(c.elements) == 0 {
+		builder.WriteLineWithDepth(indent, fmt.Sprintf("class %s%s", c.id, label))
+		return
+	}
+
+	builder.WriteLineWithDepth(indent, fmt.Sprintf("class %s%s {", c.id, label))
+	if c.annotation != "" {
+		builder.WriteLineWithDepth(indent+1, fmt.Sprintf("<<%s>>", escape(string(c.annotation))))
+	}
+	for i := range c.elements {
+		c.elements[i].Write(builder, indent+1)
+	}
+	builder.WriteLineWithDepth(indent, "}")
+}
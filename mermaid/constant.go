@@ -0,0 +1,27 @@
+package mermaid
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	constant struct {
+		name  string
+		value string
+	}
+)
+
+// Write は値に括弧を含む場合にメソッドとして解釈されないよう、括弧をエスケープして出力する。
+func (c *constant) Write(builder *LineStringBuilder, indent int) {
+	value := strings.NewReplacer("(", "#40;", ")", "#41;").Replace(escape(c.value))
+	builder.WriteLineWithDepth(indent, fmt.Sprintf("%s = %s", c.name, value))
+}
+
+// Constant は enum の値のように、型に属する定数を表す。
+func Constant(name, value string) Element {
+	return &constant{
+		name:  name,
+		value: value,
+	}
+}
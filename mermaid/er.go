@@ -0,0 +1,144 @@
+package mermaid
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	CardinalityZeroOrOne Cardinality = iota
+	CardinalityExactlyOne
+	CardinalityZeroOrMany
+	CardinalityOneOrMany
+)
+
+type (
+	// Cardinality は erDiagram の関連の端の多重度を表す。
+	Cardinality int
+
+	entity struct {
+		name       string
+		attributes []Element
+	}
+
+	attribute struct {
+		typ  string
+		name string
+		keys []string
+	}
+
+	relationship struct {
+		from            string
+		to              string
+		fromCardinality Cardinality
+		toCardinality   Cardinality
+		label           string
+	}
+)
+
+var invalidERTypeChars = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+// ERDiagram は erDiagram ブロックを出力する。
+func ERDiagram(options DiagramOptions, elements ...Element) *Result {
+	builder := newLineStringBuilder()
+	if options.Title != "" {
+		builder.WriteLineWithDepth(0, "---")
+		builder.WriteLineWithDepth(0, fmt.Sprintf("title: %s", options.Title))
+		builder.WriteLineWithDepth(0, "---")
+	}
+	builder.WriteLineWithDepth(0, "erDiagram")
+	for i := range elements {
+		elements[i].Write(builder, 1)
+	}
+	return newResult(builder)
+}
+
+// Entity は erDiagram のエンティティを返す。 name は英数字、 _ と - のみからなる必要がある。
+func Entity(name string, attributes ...Element) Element {
+	return &entity{name: name, attributes: attributes}
+}
+
+func (e *entity) Write(builder *LineStringBuilder, indent int) {
+	if len(e.attributes) == 0 {
+		builder.WriteLineWithDepth(indent, e.name)
+		return
+	}
+	builder.WriteLineWithDepth(indent, fmt.Sprintf("%s {", e.name))
+	for i := range e.attributes {
+		e.attributes[i].Write(builder, indent+1)
+	}
+	builder.WriteLineWithDepth(indent, "}")
+}
+
+// Attribute はエンティティの属性を返す。 keys には PK, FK, UK を指定できる。
+//
+// erDiagram の型は英字で始まり、英数字などしか使えないため、ポインタの * は取り除き、スライスの []T は T[] とし、
+// それ以外の文字(time.Time の . など)は _ に置き換える。
+func Attribute(typ, name string, keys ...string) Element {
+	return &attribute{typ: typ, name: name, keys: keys}
+}
+
+func (a *attribute) Write(builder *LineStringBuilder, indent int) {
+	typ := strings.TrimLeft(a.typ, "*")
+	var suffix string
+	for strings.HasPrefix(typ, "[]") {
+		typ = strings.TrimLeft(typ[2:], "*")
+		suffix += "[]"
+	}
+	typ = strings.Trim(invalidERTypeChars.ReplaceAllString(typ, "_"), "_") + suffix
+	line := fmt.Sprintf("%s %s", typ, a.name)
+	if len(a.keys) > 0 {
+		line = fmt.Sprintf("%s %s", line, strings.Join(a.keys, ", "))
+	}
+	builder.WriteLineWithDepth(indent, line)
+}
+
+// Relationship は from と to のエンティティ間の関連を返す。
+// fromCardinality は from の側の、 toCardinality は to の側の多重度を表す。
+func Relationship(from, to string, fromCardinality, toCardinality Cardinality, label string) Element {
+	return &relationship{
+		from:            from,
+		to:              to,
+		fromCardinality: fromCardinality,
+		toCardinality:   toCardinality,
+		label:           label,
+	}
+}
+
+func (r *relationship) Write(builder *LineStringBuilder, indent int) {
+	builder.WriteLineWithDepth(indent, fmt.Sprintf(
+		`%s %s--%s %s : "%s"`,
+		r.from,
+		r.fromCardinality.left(),
+		r.toCardinality.right(),
+		r.to,
+		escape(r.label),
+	))
+}
+
+func (c Cardinality) left() string {
+	switch c {
+	case CardinalityZeroOrOne:
+		return "|o"
+	case CardinalityZeroOrMany:
+		return "}o"
+	case CardinalityOneOrMany:
+		return "}|"
+	default:
+		return "||"
+	}
+}
+
+func (c Cardinality) right() string {
+	switch c {
+	case CardinalityZeroOrOne:
+		return "o|"
+	case CardinalityZeroOrMany:
+		return "o{"
+	case CardinalityOneOrMany:
+		return "|{"
+	default:
+		return "||"
+	}
+}
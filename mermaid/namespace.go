@@ -0,0 +1,30 @@
+package mermaid
+
+import "fmt"
+
+type (
+	namespace struct {
+		val      string
+		elements []Element
+	}
+)
+
+func (n *namespace) Write(builder *LineStringBuilder, indent int) {
+	builder.WriteLineWithDepth(indent, fmt.Sprintf(`namespace %s {`, n.val))
+	for i := range n.elements {
+		n.elements[i].Write(builder, indent+1)
+	}
+	builder.WriteLineWithDepth(indent, "}")
+}
+
+func Namespace(val string, elements ...Element) Element {
+	return &namespace{
+		val:      val,
+		elements: elements,
+	}
+}
+
+// NamespaceID は Mermaid の namespace 名として使えない文字を _ に置き換える。
+func NamespaceID(val string) string {
+	return invalidClassIDChars.ReplaceAllString(val, "_")
+}
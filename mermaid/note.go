@@ -0,0 +1,18 @@
+package mermaid
+
+import (
+	"fmt"
+	"strings"
+)
+
+type note struct {
+	val string
+}
+
+func (n *note) Write(builder *LineStringBuilder, indent int) {
+	builder.WriteLineWithDepth(indent, fmt.Sprintf(`note "%s"`, escape(strings.ReplaceAll(n.val, "\n", `\n`))))
+}
+
+func Note(val string) Element {
+	return &note{val: val}
+}
@@ -0,0 +1,91 @@
+package mermaid
+
+import "fmt"
+
+const (
+	RelationTypeExtension RelationType = iota
+	RelationTypeComposition
+	RelationTypeAggregation
+	RelationTypeAlias
+	RelationTypeAssociation
+	RelationTypeDependency
+)
+
+type (
+	RelationType int
+
+	// RelationOptions は関連の付加情報を表す。
+	RelationOptions struct {
+		Label string
+		// FromMultiplicity と ToMultiplicity は from と to の側に添える多重度("1", "0..1", "*" など)を表す。
+		FromMultiplicity string
+		ToMultiplicity   string
+	}
+
+	relation struct {
+		from         string
+		to           string
+		relationType RelationType
+		label        string
+
+		fromMultiplicity string
+		toMultiplicity   string
+	}
+)
+
+func (r *relation) buildRelationType() string {
+	switch r.relationType {
+	case RelationTypeExtension:
+		return `<|--`
+	case RelationTypeComposition:
+		return `*--`
+	case RelationTypeAggregation:
+		return `o--`
+	case RelationTypeAlias:
+		return `..`
+	case RelationTypeAssociation:
+		return `<--`
+	case RelationTypeDependency:
+		return `<..`
+	default:
+		return `--`
+	}
+}
+
+func (r *relation) Write(builder *LineStringBuilder, indent int) {
+	typ := r.buildRelationType()
+	if r.toMultiplicity != "" {
+		typ = fmt.Sprintf(`"%s" %s`, r.toMultiplicity, typ)
+	}
+	if r.fromMultiplicity != "" {
+		typ = fmt.Sprintf(`%s "%s"`, typ, r.fromMultiplicity)
+	}
+	line := fmt.Sprintf(
+		`%s %s %s`,
+		r.to,
+		typ,
+		r.from,
+	)
+	if r.label != "" {
+		line = fmt.Sprintf("%s : %s", line, escape(r.label))
+	}
+	builder.WriteLineWithDepth(indent, line)
+}
+
+// Relation は from と to のクラスIDを結ぶ関連を表す。
+// plantuml.Relation と同様に、出力時は to が左辺、 from が右辺となる。
+func Relation(from, to string, relationType RelationType) Element {
+	return RelationWithOption(from, to, relationType, RelationOptions{})
+}
+
+func RelationWithOption(from, to string, relationType RelationType, options RelationOptions) Element {
+	return &relation{
+		from:         from,
+		to:           to,
+		relationType: relationType,
+		label:        options.Label,
+
+		fromMultiplicity: options.FromMultiplicity,
+		toMultiplicity:   options.ToMultiplicity,
+	}
+}
@@ -19,12 +19,15 @@ type (
 		as         string
 		stereotype Stereotype
 		spot       Spot
+		color      *Color
 	}
 
 	ClassOptions struct {
 		As         string
 		Spot       Spot
 		Stereotype Stereotype
+		// Color は背景色を表す。
+		Color *Color
 	}
 
 	Spot struct {
@@ -68,6 +71,7 @@ func ClassWithOption(name string, options ClassOptions, elements ...Element) Ele
 		as:         options.As,
 		stereotype: options.Stereotype,
 		spot:       options.Spot,
+		color:      options.Color,
 	}
 }
 
@@ -77,16 +81,22 @@ func (c *class) Write(builder *LineStringBuilder, indent int) {
 		as = fmt.Sprintf(`as %s`, c.as)
 	}
 
-	builder.WriteLineWithDepth(indent, fmt.Sprintf(`class "%s" %s %s {`, c.name, as, c.buildStereotype()))
+	builder.WriteLineWithDepth(indent, fmt.Sprintf(
+		`class "%s" %s %s%s {`,
+		c.name,
+		as,
+		buildStereotype(c.spot, c.stereotype),
+		backgroundColor(c.color),
+	))
 	for i := range c.elements {
 		c.elements[i].Write(builder, indent+1)
 	}
 	builder.WriteLineWithDepth(indent, "}")
 }
 
-func (c *class) buildStereotype() string {
-	sp := c.spot.build()
-	st := c.stereotype.build()
+func buildStereotype(spot Spot, stereotype Stereotype) string {
+	sp := spot.build()
+	st := stereotype.build()
 	if sp != "" || st != "" {
 		return fmt.Sprintf("<< %s %s >>", sp, st)
 	}
@@ -42,3 +42,24 @@ func (c *Color) HexRGBA() string {
 	hexColor := (uint64(c.r) << 24) | (uint64(c.g) << 16) | (uint64(c.b) << 8) | uint64(c.a)
 	return fmt.Sprintf("%08s", strconv.FormatUint(hexColor, 16))
 }
+
+// HexRGB は透明度を含まない16進数の表記を返す。
+func (c *Color) HexRGB() string {
+	return fmt.Sprintf("%02x%02x%02x", c.r, c.g, c.b)
+}
+
+// colored は text を c の色の文字とする creole を返す。 c が nil の場合は text をそのまま返す。
+func colored(c *Color, text string) string {
+	if c == nil {
+		return text
+	}
+	return fmt.Sprintf("<color:#%s>%s</color>", c.HexRGB(), text)
+}
+
+// backgroundColor は要素の背景色の指定を返す。 c が nil の場合は空文字列を返す。
+func backgroundColor(c *Color) string {
+	if c == nil {
+		return ""
+	}
+	return fmt.Sprintf(" #%s", c.HexRGB())
+}
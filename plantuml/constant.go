@@ -0,0 +1,29 @@
+package plantuml
+
+import "fmt"
+
+type (
+	constant struct {
+		name  string
+		value string
+		color *Color
+	}
+)
+
+// Write は値に括弧を含む場合にメソッドとして解釈されないよう {field} を付与して出力する。
+func (c *constant) Write(builder *LineStringBuilder, indent int) {
+	builder.WriteLineWithDepth(indent, fmt.Sprintf("{field} %s", colored(c.color, fmt.Sprintf("%s = %s", c.name, c.value))))
+}
+
+// Constant は enum の値のように、型に属する定数を表す。
+func Constant(name, value string) Element {
+	return ConstantWithOption(name, value, MemberOptions{})
+}
+
+func ConstantWithOption(name, value string, options MemberOptions) Element {
+	return &constant{
+		name:  name,
+		value: value,
+		color: options.Color,
+	}
+}
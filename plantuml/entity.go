@@ -0,0 +1,145 @@
+package plantuml
+
+import "fmt"
+
+const (
+	CardinalityZeroOrOne Cardinality = iota
+	CardinalityExactlyOne
+	CardinalityZeroOrMany
+	CardinalityOneOrMany
+)
+
+type (
+	// Cardinality は ER 図の関連の端の多重度を表す。
+	Cardinality int
+
+	entity struct {
+		name    string
+		as      string
+		keys    []Element
+		columns []Element
+	}
+
+	// EntityOptions は ER 図のエンティティの付加情報を表す。
+	EntityOptions struct {
+		As string
+	}
+
+	column struct {
+		name       string
+		typ        string
+		mandatory  bool
+		stereotype Stereotype
+	}
+
+	// ColumnOptions は ER 図のエンティティの列の付加情報を表す。
+	ColumnOptions struct {
+		// Mandatory は NULL を許容しない列であることを表す。
+		Mandatory  bool
+		Stereotype Stereotype
+	}
+
+	entityRelationship struct {
+		from            string
+		to              string
+		fromCardinality Cardinality
+		toCardinality   Cardinality
+		label           string
+	}
+)
+
+// Entity は ER 図のエンティティを返す。 keys は主キーの列とし、区切り線の上に出力する。
+func Entity(name string, options EntityOptions, keys []Element, columns []Element) Element {
+	return &entity{
+		name:    name,
+		as:      options.As,
+		keys:    keys,
+		columns: columns,
+	}
+}
+
+func (e *entity) Write(builder *LineStringBuilder, indent int) {
+	var as string
+	if e.as != "" && e.as != e.name {
+		as = fmt.Sprintf(" as %s", e.as)
+	}
+	builder.WriteLineWithDepth(indent, fmt.Sprintf(`entity "%s"%s {`, e.name, as))
+	for i := range e.keys {
+		e.keys[i].Write(builder, indent+1)
+	}
+	if len(e.keys) > 0 {
+		builder.WriteLineWithDepth(indent+1, "--")
+	}
+	for i := range e.columns {
+		e.columns[i].Write(builder, indent+1)
+	}
+	builder.WriteLineWithDepth(indent, "}")
+}
+
+// Column は ER 図のエンティティの列を返す。
+func Column(name, typ string, options ColumnOptions) Element {
+	return &column{
+		name:       name,
+		typ:        typ,
+		mandatory:  options.Mandatory,
+		stereotype: options.Stereotype,
+	}
+}
+
+func (c *column) Write(builder *LineStringBuilder, indent int) {
+	var mark string
+	if c.mandatory {
+		mark = "* "
+	}
+	line := fmt.Sprintf("%s%s : %s", mark, c.name, c.typ)
+	if st := c.stereotype.build(); st != "" {
+		line = fmt.Sprintf("%s <<%s>>", line, st)
+	}
+	builder.WriteLineWithDepth(indent, line)
+}
+
+// EntityRelationship は from と to のエンティティ間の関連を返す。
+// fromCardinality は from の側の、 toCardinality は to の側の多重度を表す。
+func EntityRelationship(from, to string, fromCardinality, toCardinality Cardinality, label string) Element {
+	return &entityRelationship{
+		from:            from,
+		to:              to,
+		fromCardinality: fromCardinality,
+		toCardinality:   toCardinality,
+		label:           label,
+	}
+}
+
+func (r *entityRelationship) Write(builder *LineStringBuilder, indent int) {
+	line := fmt.Sprintf("%s %s--%s %s", r.from, r.fromCardinality.left(), r.toCardinality.right(), r.to)
+	if r.label != "" {
+		line = fmt.Sprintf("%s : %s", line, r.label)
+	}
+	builder.WriteLineWithDepth(indent, line)
+}
+
+func (c Cardinality) left() string {
+	switch c {
+	case CardinalityZeroOrOne:
+		return "|o"
+	case CardinalityZeroOrMany:
+		return "}o"
+	case CardinalityOneOrMany:
+		return "}|"
+	default:
+		return "||"
+	}
+}
+
+func (c Cardinality) right() string {
+	switch c {
+	case CardinalityZeroOrOne:
+		return "o|"
+	case CardinalityZeroOrMany:
+		return "o{"
+	case CardinalityOneOrMany:
+		return "|{"
+	default:
+		return "||"
+	}
+}
@@ -7,18 +7,41 @@ type (
 		accessModifier AccessModifier
 		name           string
 		typ            string
+		tag            string
+		color          *Color
+	}
+
+	// MemberOptions はフィールド、メソッド、定数の付加情報を表す。
+	MemberOptions struct {
+		// Color は文字色を表す。
+		Color *Color
+		// Tag はフィールドの場合に、型の後ろに添える struct tag を表す。
+		Tag string
 	}
 )
 
 func (f *field) Write(builder *LineStringBuilder, indent int) {
-	s := fmt.Sprintf("%s %s %s", f.accessModifier.toString(), f.name, f.typ)
-	builder.WriteLineWithDepth(indent, s)
+	s := fmt.Sprintf("%s %s", f.name, f.typ)
+	if f.tag != "" {
+		s = fmt.Sprintf("%s `%s`", s, f.tag)
+	}
+	if f.color == nil {
+		builder.WriteLineWithDepth(indent, fmt.Sprintf("%s %s", f.accessModifier.toString(), s))
+		return
+	}
+	builder.WriteLineWithDepth(indent, fmt.Sprintf("%s %s", f.accessModifier.toString(), colored(f.color, s)))
 }
 
 func Field(accessModifier AccessModifier, name, typ string) Element {
+	return FieldWithOption(accessModifier, name, typ, MemberOptions{})
+}
+
+func FieldWithOption(accessModifier AccessModifier, name, typ string, options MemberOptions) Element {
 	return &field{
 		accessModifier: accessModifier,
 		name:           name,
 		typ:            typ,
+		tag:            options.Tag,
+		color:          options.Color,
 	}
 }
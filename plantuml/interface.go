@@ -4,22 +4,56 @@ import "fmt"
 
 type (
 	iface struct {
-		name     string
-		elements []Element
+		name       string
+		elements   []Element
+		as         string
+		stereotype Stereotype
+		spot       Spot
+		color      *Color
+	}
+
+	InterfaceOptions struct {
+		As         string
+		Spot       Spot
+		Stereotype Stereotype
+		// Color は背景色を表す。
+		Color *Color
 	}
 )
 
 func (i *iface) Write(builder *LineStringBuilder, indent int) {
-	builder.WriteLineWithDepth(indent, fmt.Sprintf("interface %s {", i.name))
+	builder.WriteLineWithDepth(indent, fmt.Sprintf("interface %s%s {", i.buildDeclaration(), backgroundColor(i.color)))
 	for ei := range i.elements {
 		i.elements[ei].Write(builder, indent+1)
 	}
 	builder.WriteLineWithDepth(indent, "}")
 }
 
+// buildDeclaration はオプションが指定されていない場合、従来どおり名前のみを返す。
+func (i *iface) buildDeclaration() string {
+	stereotype := buildStereotype(i.spot, i.stereotype)
+	if i.as == "" && stereotype == "" {
+		return i.name
+	}
+
+	var as string
+	if i.as != "" {
+		as = fmt.Sprintf(`as %s`, i.as)
+	}
+	return fmt.Sprintf(`"%s" %s %s`, i.name, as, stereotype)
+}
+
 func Interface(name string, elements ...Element) Element {
+	return InterfaceWithOption(name, InterfaceOptions{}, elements...)
+}
+
+func InterfaceWithOption(name string, options InterfaceOptions, elements ...Element) Element {
 	return &iface{
-		name:     name,
-		elements: elements,
+		name:       name,
+		elements:   elements,
+		as:         options.As,
+		stereotype: options.Stereotype,
+		spot:       options.Spot,
+		color:      options.Color,
 	}
 }
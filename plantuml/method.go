@@ -11,6 +11,7 @@ type (
 		name           string
 		parameters     Params
 		returnValues   ReturnValues
+		color          *Color
 	}
 
 	Params []Param
@@ -70,12 +71,20 @@ func (m *method) buildReturnValues() string {
 }
 
 func (m *method) Write(builder *LineStringBuilder, indent int) {
+	if m.color == nil {
+		builder.WriteLineWithDepth(indent, fmt.Sprintf(
+			"%s %s(%s) %s",
+			m.accessModifier.toString(),
+			m.name,
+			m.buildParameters(),
+			m.buildReturnValues(),
+		))
+		return
+	}
 	builder.WriteLineWithDepth(indent, fmt.Sprintf(
-		"%s %s(%s) %s",
+		"%s %s",
 		m.accessModifier.toString(),
-		m.name,
-		m.buildParameters(),
-		m.buildReturnValues(),
+		colored(m.color, strings.TrimSpace(fmt.Sprintf("%s(%s) %s", m.name, m.buildParameters(), m.buildReturnValues()))),
 	))
 }
 
@@ -84,11 +93,22 @@ func Method(
 	name string,
 	parameters Params,
 	returnValues ReturnValues,
+) Element {
+	return MethodWithOption(accessModifier, name, parameters, returnValues, MemberOptions{})
+}
+
+func MethodWithOption(
+	accessModifier AccessModifier,
+	name string,
+	parameters Params,
+	returnValues ReturnValues,
+	options MemberOptions,
 ) Element {
 	return &method{
 		accessModifier: accessModifier,
 		name:           name,
 		parameters:     parameters,
 		returnValues:   returnValues,
+		color:          options.Color,
 	}
 }
@@ -6,11 +6,14 @@ type (
 	namespace struct {
 		val      string
 		as       string
+		color    *Color
 		elements []Element
 	}
 
 	NamespaceOptions struct {
 		As string
+		// Color は背景色を表す。
+		Color *Color
 	}
 )
 
@@ -21,9 +24,9 @@ func (n *namespace) Write(builder *LineStringBuilder, indent int) {
 	}
 
 	if as == "" {
-		builder.WriteLineWithDepth(indent, fmt.Sprintf(`namespace %s {`, n.val))
+		builder.WriteLineWithDepth(indent, fmt.Sprintf(`namespace %s%s {`, n.val, backgroundColor(n.color)))
 	} else {
-		builder.WriteLineWithDepth(indent, fmt.Sprintf(`namespace %s %s {`, n.val, as))
+		builder.WriteLineWithDepth(indent, fmt.Sprintf(`namespace %s %s%s {`, n.val, as, backgroundColor(n.color)))
 	}
 
 	for i := range n.elements {
@@ -44,5 +47,6 @@ func NamespaceWithOption(val string, options NamespaceOptions, elements ...Eleme
 		val:      val,
 		elements: elements,
 		as:       options.As,
+		color:    options.Color,
 	}
 }
@@ -0,0 +1,50 @@
+package plantuml
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	NotePositionTop NotePosition = iota
+	NotePositionBottom
+	NotePositionLeft
+	NotePositionRight
+)
+
+type (
+	// NotePosition は注釈を添える対象からみた注釈の位置を表す。
+	NotePosition int
+
+	note struct {
+		target   RelationTarget
+		position NotePosition
+		text     string
+	}
+)
+
+func (p NotePosition) toString() string {
+	switch p {
+	case NotePositionBottom:
+		return "bottom"
+	case NotePositionLeft:
+		return "left"
+	case NotePositionRight:
+		return "right"
+	default:
+		return "top"
+	}
+}
+
+// NoteOf は target のクラスに添える注釈を表す。 text は複数行を含められる。
+func NoteOf(target RelationTarget, position NotePosition, text string) Element {
+	return &note{target: target, position: position, text: text}
+}
+
+func (n *note) Write(builder *LineStringBuilder, indent int) {
+	builder.WriteLineWithDepth(indent, fmt.Sprintf(`note %s of "%s"`, n.position.toString(), n.target.String()))
+	for _, line := range strings.Split(n.text, "\n") {
+		builder.WriteLineWithDepth(indent, line)
+	}
+	builder.WriteLineWithDepth(indent, "end note")
+}
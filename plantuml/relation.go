@@ -1,6 +1,9 @@
 package plantuml
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 const (
 	RelationTypeExtension RelationType = iota
@@ -8,6 +11,7 @@ const (
 	RelationTypeAggregation
 	RelationTypeAlias
 	RelationTypeArrow
+	RelationTypeDependency
 )
 
 type (
@@ -17,6 +21,21 @@ type (
 		from         RelationTarget
 		to           RelationTarget
 		relationType RelationType
+		label        string
+		color        *Color
+		// fromMultiplicity と toMultiplicity は from と to の側に添える多重度を表す。
+		fromMultiplicity string
+		toMultiplicity   string
+	}
+
+	// RelationOptions は関連の付加情報を表す。
+	RelationOptions struct {
+		Label string
+		// FromMultiplicity と ToMultiplicity は from と to の側に添える多重度("1", "0..1", "*" など)を表す。
+		FromMultiplicity string
+		ToMultiplicity   string
+		// Color は線の色を表す。
+		Color *Color
 	}
 
 	RelationTarget struct {
@@ -57,25 +76,56 @@ func (r *relation) buildRelationType() string {
 		return `#..`
 	case RelationTypeArrow:
 		return `<--`
+	case RelationTypeDependency:
+		return `<..`
 	default:
 		return `--`
 	}
 }
 
-func (r *relation) Write(builder *LineStringBuilder, indent int) {
+// buildColoredRelationType は線の最初の - または . の後に色の指定を挿入する。(<|-- は <|-[#rrggbb]- となる)
+func (r *relation) buildColoredRelationType() string {
 	typ := r.buildRelationType()
-	builder.WriteLineWithDepth(indent, fmt.Sprintf(
+	if r.color == nil {
+		return typ
+	}
+	i := strings.IndexAny(typ, "-.")
+	return fmt.Sprintf("%s[#%s]%s", typ[:i+1], r.color.HexRGB(), typ[i+1:])
+}
+
+func (r *relation) Write(builder *LineStringBuilder, indent int) {
+	typ := r.buildColoredRelationType()
+	if r.toMultiplicity != "" {
+		typ = fmt.Sprintf(`"%s" %s`, r.toMultiplicity, typ)
+	}
+	if r.fromMultiplicity != "" {
+		typ = fmt.Sprintf(`%s "%s"`, typ, r.fromMultiplicity)
+	}
+	line := fmt.Sprintf(
 		`"%s" %s "%s"`,
 		r.to.String(),
 		typ,
 		r.from.String(),
-	))
+	)
+	if r.label != "" {
+		line = fmt.Sprintf("%s : %s", line, r.label)
+	}
+	builder.WriteLineWithDepth(indent, line)
 }
 
 func Relation(from, to RelationTarget, relationType RelationType) Element {
+	return RelationWithOption(from, to, relationType, RelationOptions{})
+}
+
+func RelationWithOption(from, to RelationTarget, relationType RelationType, options RelationOptions) Element {
 	return &relation{
 		from:         from,
 		to:           to,
 		relationType: relationType,
+		label:        options.Label,
+		color:        options.Color,
+
+		fromMultiplicity: options.FromMultiplicity,
+		toMultiplicity:   options.ToMultiplicity,
 	}
 }
@@ -0,0 +1,93 @@
+package plantuml
+
+import "fmt"
+
+type (
+	participant struct {
+		name       string
+		as         string
+		stereotype Stereotype
+	}
+
+	// ParticipantOptions はシーケンス図の参加者の付加情報を表す。
+	ParticipantOptions struct {
+		As         string
+		Stereotype Stereotype
+	}
+
+	message struct {
+		from     string
+		to       string
+		label    string
+		async    bool
+		elements []Element
+	}
+
+	// MessageOptions はシーケンス図のメッセージの付加情報を表す。
+	MessageOptions struct {
+		// Async は呼び出し元が応答を待たないメッセージ(goroutine の起動など)であることを表す。
+		Async bool
+	}
+)
+
+func Participant(name string, options ParticipantOptions) Element {
+	return &participant{
+		name:       name,
+		as:         options.As,
+		stereotype: options.Stereotype,
+	}
+}
+
+func (p *participant) Write(builder *LineStringBuilder, indent int) {
+	var as string
+	if p.as != "" {
+		as = fmt.Sprintf(" as %s", p.as)
+	}
+	var stereotype string
+	if st := buildStereotype(Spot{}, p.stereotype); st != "" {
+		stereotype = " " + st
+	}
+	builder.WriteLineWithDepth(indent, fmt.Sprintf(`participant "%s"%s%s`, p.name, as, stereotype))
+}
+
+// Message は from から to へのメッセージを返す。
+// to をメッセージの間だけ活性化し、 elements を to から送るメッセージとしてネストして出力する。
+// from が空文字列の場合は図の外からのメッセージとする。
+func Message(from, to, label string, elements ...Element) Element {
+	return MessageWithOption(from, to, label, MessageOptions{}, elements...)
+}
+
+func MessageWithOption(from, to, label string, options MessageOptions, elements ...Element) Element {
+	return &message{
+		from:     from,
+		to:       to,
+		label:    label,
+		async:    options.Async,
+		elements: elements,
+	}
+}
+
+func (m *message) Write(builder *LineStringBuilder, indent int) {
+	arrow := "->"
+	if m.async {
+		arrow = "->>"
+	}
+	if m.from == "" {
+		builder.WriteLineWithDepth(indent, fmt.Sprintf("[%s %s : %s", arrow, m.to, m.label))
+	} else {
+		builder.WriteLineWithDepth(indent, fmt.Sprintf("%s %s %s : %s", m.from, arrow, m.to, m.label))
+	}
+	builder.WriteLineWithDepth(indent, fmt.Sprintf("activate %s", m.to))
+	for i := range m.elements {
+		m.elements[i].Write(builder, indent+1)
+	}
+	// 応答を待つメッセージのみ、呼び出し元への戻りを点線で出力する。自身への呼び出しでは省略する。
+	if !m.async && m.from != m.to {
+		if m.from == "" {
+			builder.WriteLineWithDepth(indent, fmt.Sprintf("[<-- %s", m.to))
+		} else {
+			builder.WriteLineWithDepth(indent, fmt.Sprintf("%s --> %s", m.to, m.from))
+		}
+	}
+	builder.WriteLineWithDepth(indent, fmt.Sprintf("deactivate %s", m.to))
+}
@@ -0,0 +1,63 @@
+package apisurface
+
+type (
+	Client struct {
+		*transport
+		logger
+		Endpoint string
+		timeout  int
+	}
+
+	transport struct {
+		base
+		Retries int
+		conn    connection
+	}
+
+	base struct {
+		UserAgent string
+		secret    string
+	}
+
+	logger interface {
+		Logf(format string, args ...interface{})
+	}
+
+	connection interface {
+		Send(payload []byte) error
+	}
+
+	Request struct {
+		Path string
+	}
+
+	Response struct {
+		Status int
+	}
+)
+
+func NewClient(endpoint string) *Client {
+	return &Client{Endpoint: endpoint}
+}
+
+func newTransport() *transport {
+	return &transport{}
+}
+
+func (c *Client) Do(req *Request) (*Response, error) {
+	return c.roundTrip(req)
+}
+
+func (c *Client) roundTrip(req *Request) (*Response, error) {
+	return &Response{}, nil
+}
+
+func (t *transport) Close() error {
+	return nil
+}
+
+func (t *transport) reset() {}
+
+func (b base) Version() string {
+	return "v1"
+}
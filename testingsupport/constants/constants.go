@@ -0,0 +1,28 @@
+//lint:file-ignore U1000 Ignore all unused code, it's for testing support.
+package constants
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelError
+	levelUnknown
+)
+
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json(compact)"
+)
+
+const DefaultName = "default"
+
+var DefaultLevel = LevelInfo
+
+var formats = []Format{FormatText, FormatJSON}
+
+func (l Level) Enabled(min Level) bool {
+	return l >= min
+}
@@ -0,0 +1,37 @@
+package shop
+
+type Status int
+
+const (
+	StatusOpen Status = iota
+	StatusClosed
+)
+
+type Item struct {
+	Name  string
+	Price int
+}
+
+type Coupon struct {
+	Code string
+	Rate float64
+}
+
+type Order struct {
+	ID     string
+	Status Status
+	Items  []Item
+	coupon *Coupon
+	note   string
+}
+
+func (o *Order) Total() int {
+	return 0
+}
+
+func (o *Order) Cancel() {}
+
+type Repository interface {
+	Find(id string) (*Order, error)
+	Save(order *Order) error
+}
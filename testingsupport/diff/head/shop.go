@@ -0,0 +1,40 @@
+package shop
+
+type Status int
+
+const (
+	StatusOpen Status = iota
+	StatusShipped
+	StatusClosed
+)
+
+type Item struct {
+	Name  string
+	Price int
+}
+
+type Discount struct {
+	Code string
+	Rate float64
+}
+
+type Order struct {
+	ID       string
+	Status   Status
+	Items    []*Item
+	Discount *Discount
+}
+
+func (o *Order) Total() int64 {
+	return 0
+}
+
+func (o *Order) Ship() error {
+	return nil
+}
+
+type Repository interface {
+	Find(id string) (*Order, error)
+	Save(order *Order) error
+	Delete(id string) error
+}
@@ -0,0 +1,50 @@
+package erd
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Model は gorm.Model と同様に、全てのテーブルに共通する列を表す。
+type Model struct {
+	ID        uint64 `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// User は db タグで列名を指定する。
+type User struct {
+	ID       int64          `db:"id"`
+	Email    string         `db:"email"`
+	Nickname sql.NullString `db:"nickname"`
+	Password string         `db:"-"`
+	Orders   []Order
+}
+
+// Order は gorm タグで列名と外部キーを指定する。
+type Order struct {
+	Model
+	UserID   int64  `gorm:"column:user_id;not null"`
+	CouponID *int64 `gorm:"column:coupon_id"`
+	Buyer    User   `gorm:"foreignKey:UserID"`
+	Items    []OrderItem
+	note     string
+}
+
+// OrderItem は sql タグで複合主キーを指定する。
+type OrderItem struct {
+	OrderID   uint64 `sql:"order_id,pk"`
+	ProductID string `sql:"product_id,pk"`
+	Quantity  int    `sql:"quantity"`
+}
+
+// Coupon は XxxID の命名から参照されるテーブル。
+type Coupon struct {
+	ID   int64  `db:"id"`
+	Code string `db:"code"`
+}
+
+// Price はタグを持たないためテーブルとしない。
+type Price struct {
+	Amount int
+}
@@ -0,0 +1,27 @@
+package filters
+
+type (
+	Order struct {
+		ID     int
+		Status Status
+	}
+
+	Status int
+
+	OrderRepository interface {
+		Find(id int) (*Order, error)
+	}
+
+	OrderService struct {
+		repository OrderRepository
+		cache      *orderCache
+	}
+
+	orderCache struct {
+		orders map[int]*Order
+	}
+)
+
+func (s *OrderService) Find(id int) (*Order, error) {
+	return s.repository.Find(id)
+}
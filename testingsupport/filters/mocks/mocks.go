@@ -0,0 +1,13 @@
+package mocks
+
+import "github.com/keisuke-m123/godiagramgen/testingsupport/filters"
+
+type (
+	MockOrderRepository struct {
+		Orders map[int]*filters.Order
+	}
+)
+
+func (m *MockOrderRepository) Find(id int) (*filters.Order, error) {
+	return m.Orders[id], nil
+}
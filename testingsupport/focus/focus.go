@@ -0,0 +1,48 @@
+package focus
+
+type (
+	Address struct {
+		City string
+	}
+
+	Customer struct {
+		Name    string
+		Address Address
+	}
+
+	Item struct {
+		Name  string
+		Price int
+	}
+
+	Order struct {
+		ID       int
+		Customer *Customer
+		Items    []*Item
+	}
+
+	OrderRepository interface {
+		Save(order *Order) error
+	}
+
+	OrderService struct {
+		repository OrderRepository
+	}
+
+	memoryOrderRepository struct {
+		orders map[int]*Order
+	}
+
+	Unrelated struct {
+		Value string
+	}
+)
+
+func (r *memoryOrderRepository) Save(order *Order) error {
+	r.orders[order.ID] = order
+	return nil
+}
+
+func (s *OrderService) Place(order *Order) error {
+	return s.repository.Save(order)
+}
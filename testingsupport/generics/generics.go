@@ -0,0 +1,48 @@
+//lint:file-ignore U1000 Ignore all unused code, it's for testing support.
+package generics
+
+type Number interface {
+	~int | ~int64 | ~float64
+}
+
+type Stringer interface {
+	String() string
+}
+
+type Cache[K comparable, V any] struct {
+	items    map[K]V
+	fallback *V
+}
+
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	v, ok := c.items[key]
+	return v, ok
+}
+
+type Repository[T Stringer] interface {
+	Find(id string) (T, error)
+}
+
+type List[T Number] []T
+
+func (l List[T]) Sum() T {
+	var sum T
+	for _, v := range l {
+		sum += v
+	}
+	return sum
+}
+
+type User struct {
+	name string
+}
+
+func (u *User) String() string {
+	return u.name
+}
+
+type UserStore struct {
+	cache  Cache[string, *User]
+	repo   Repository[*User]
+	scores List[int]
+}
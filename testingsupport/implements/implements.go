@@ -0,0 +1,50 @@
+package implements
+
+// Shape は他のパッケージの型にも実装される interface。
+type Shape interface {
+	Area() float64
+}
+
+// Unused は実装する型の無い interface。
+type Unused interface {
+	Unused()
+}
+
+// Square は値レシーバのメソッドで Shape を実装する。
+type Square struct {
+	Side float64
+}
+
+func (s Square) Area() float64 {
+	return s.Side * s.Side
+}
+
+// Celsius は defined type として fmt.Stringer を実装する。
+type Celsius float64
+
+func (c Celsius) String() string {
+	return "celsius"
+}
+
+// Buffer はポインタレシーバのメソッドで io.Reader と io.Writer を実装する。
+type Buffer []byte
+
+func (b *Buffer) Read(p []byte) (int, error) {
+	n := copy(p, *b)
+	*b = (*b)[n:]
+	return n, nil
+}
+
+func (b *Buffer) Write(p []byte) (int, error) {
+	*b = append(*b, p...)
+	return len(p), nil
+}
+
+// NotFoundError はポインタレシーバのメソッドで error を実装する。
+type NotFoundError struct {
+	Name string
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Name + " not found"
+}
@@ -0,0 +1,17 @@
+package shapes
+
+// Circle は implements パッケージを import せずに implements.Shape を実装する。
+type Circle float64
+
+func (c Circle) Area() float64 {
+	return 3.14 * float64(c) * float64(c)
+}
+
+// Polygon は Area のシグネチャが異なるため implements.Shape を実装しない。
+type Polygon struct {
+	Sides int
+}
+
+func (p Polygon) Area() int {
+	return p.Sides
+}
@@ -0,0 +1,27 @@
+package imports
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/keisuke-m123/godiagramgen/testingsupport/imports/store"
+	"github.com/spf13/afero"
+	"github.com/spf13/afero/mem"
+)
+
+type Loader struct {
+	fs    afero.Fs
+	store *store.Store
+}
+
+func (l *Loader) Load(name string) (*mem.FileData, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("empty name")
+	}
+	return mem.CreateFile(name), nil
+}
+
+func (l *Loader) Equal(a, b *store.Store) bool {
+	return cmp.Equal(a, b)
+}
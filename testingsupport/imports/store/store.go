@@ -0,0 +1,8 @@
+package store
+
+import "sync"
+
+type Store struct {
+	mu    sync.Mutex
+	items map[string]string
+}
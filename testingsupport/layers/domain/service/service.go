@@ -0,0 +1,14 @@
+package service
+
+import (
+	"github.com/keisuke-m123/godiagramgen/testingsupport/layers/domain/model"
+	"github.com/keisuke-m123/godiagramgen/testingsupport/layers/infra/db"
+)
+
+type UserService struct {
+	db *db.UserTable
+}
+
+func (s *UserService) Find(id string) *model.User {
+	return s.db.Get(id)
+}
@@ -0,0 +1,11 @@
+package db
+
+import "github.com/keisuke-m123/godiagramgen/testingsupport/layers/domain/model"
+
+type UserTable struct {
+	rows map[string]*model.User
+}
+
+func (t *UserTable) Get(id string) *model.User {
+	return t.rows[id]
+}
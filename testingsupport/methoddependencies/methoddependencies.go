@@ -0,0 +1,49 @@
+package methoddependencies
+
+import "context"
+
+type (
+	User struct {
+		ID   int
+		Name string
+	}
+
+	Users []*User
+
+	UserRepository interface {
+		Find(ctx context.Context, id int) (*User, error)
+		FindAll(ctx context.Context) (Users, error)
+	}
+
+	AuditLogger interface {
+		Log(user *User, message string)
+	}
+
+	UserService struct {
+		logger AuditLogger
+	}
+)
+
+func (s *UserService) Rename(ctx context.Context, repo UserRepository, id int, name string) (*User, error) {
+	user, err := repo.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	user.Name = name
+	s.logger.Log(user, "renamed")
+	return user, nil
+}
+
+func (s *UserService) List(ctx context.Context, repo UserRepository) (Users, error) {
+	return repo.FindAll(ctx)
+}
+
+func (us Users) Filter(match func(user *User) bool) Users {
+	var filtered Users
+	for _, u := range us {
+		if match(u) {
+			filtered = append(filtered, u)
+		}
+	}
+	return filtered
+}
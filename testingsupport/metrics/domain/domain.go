@@ -0,0 +1,10 @@
+package domain
+
+type Order struct {
+	ID    string
+	Total int
+}
+
+type OrderRepository interface {
+	Find(id string) *Order
+}
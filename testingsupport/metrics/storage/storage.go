@@ -0,0 +1,11 @@
+package storage
+
+import "github.com/keisuke-m123/godiagramgen/testingsupport/metrics/domain"
+
+type OrderTable struct {
+	rows map[string]*domain.Order
+}
+
+func (t *OrderTable) Find(id string) *domain.Order {
+	return t.rows[id]
+}
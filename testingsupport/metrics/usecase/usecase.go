@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"github.com/keisuke-m123/godiagramgen/testingsupport/metrics/domain"
+	"github.com/keisuke-m123/godiagramgen/testingsupport/metrics/storage"
+)
+
+type OrderUsecase struct {
+	repository domain.OrderRepository
+}
+
+func NewOrderUsecase(table *storage.OrderTable) *OrderUsecase {
+	return &OrderUsecase{repository: table}
+}
+
+func (u *OrderUsecase) Total(id string) int {
+	return u.repository.Find(id).Total
+}
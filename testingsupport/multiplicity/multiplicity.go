@@ -0,0 +1,37 @@
+package multiplicity
+
+// Order は注文を表す。
+type Order struct {
+	items    []*LineItem
+	customer *Customer
+	billing  Address
+	shipping *Address
+	coupons  map[string]Coupon
+	History
+}
+
+// LineItem は注文の明細を表す。
+type LineItem struct {
+	quantity int
+}
+
+// Customer は注文した顧客を表す。
+type Customer struct {
+	name      string
+	addresses [2]Address
+}
+
+// Address は住所を表す。
+type Address struct {
+	city string
+}
+
+// Coupon は注文に適用する割引を表す。
+type Coupon struct {
+	code string
+}
+
+// History は注文の履歴を表す。
+type History struct {
+	events []string
+}
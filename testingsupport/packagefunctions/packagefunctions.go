@@ -0,0 +1,38 @@
+//lint:file-ignore U1000 Ignore all unused code, it's for testing support.
+package packagefunctions
+
+import "strings"
+
+type Client struct {
+	name string
+}
+
+type Option func(*Client)
+
+func NewClient(name string, options ...Option) *Client {
+	c := &Client{name: name}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+func NewDefaultClient() (*Client, error) {
+	return NewClient("default"), nil
+}
+
+func newOption() Option {
+	return func(*Client) {}
+}
+
+func Join(values []string) string {
+	return strings.Join(values, ",")
+}
+
+func Map[T, U any](values []T, f func(T) U) []U {
+	mapped := make([]U, 0, len(values))
+	for _, v := range values {
+		mapped = append(mapped, f(v))
+	}
+	return mapped
+}
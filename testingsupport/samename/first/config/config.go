@@ -0,0 +1,14 @@
+//lint:file-ignore U1000 Ignore all unused code, it's generated
+package config
+
+type Loader interface {
+	Load() Config
+}
+
+type Config struct {
+	Name string
+}
+
+func (c Config) Load() Config {
+	return c
+}
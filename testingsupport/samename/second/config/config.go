@@ -0,0 +1,18 @@
+//lint:file-ignore U1000 Ignore all unused code, it's generated
+package config
+
+import (
+	firstconfig "github.com/keisuke-m123/godiagramgen/testingsupport/samename/first/config"
+)
+
+type Loader interface {
+	Reload() error
+}
+
+type Config struct {
+	Base *firstconfig.Config
+}
+
+func (c *Config) Reload() error {
+	return nil
+}
@@ -0,0 +1,7 @@
+package audit
+
+import "log"
+
+func Record(action, id string) {
+	log.Printf("%s %s", action, id)
+}
@@ -0,0 +1,59 @@
+package sequence
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/keisuke-m123/godiagramgen/testingsupport/sequence/audit"
+)
+
+type OrderHandler struct {
+	service *OrderService
+}
+
+func (h *OrderHandler) Handle(id string) error {
+	order, err := h.service.Place(id)
+	if err != nil {
+		return err
+	}
+	go h.notify(order)
+	return nil
+}
+
+func (h *OrderHandler) notify(order *Order) {
+	fmt.Println(order.ID)
+}
+
+type OrderService struct {
+	repository OrderRepository
+}
+
+func (s *OrderService) Place(id string) (*Order, error) {
+	order := NewOrder(id)
+	if err := order.Validate(); err != nil {
+		return nil, err
+	}
+	defer func() {
+		audit.Record("place", order.ID)
+	}()
+	return order, s.repository.Save(order)
+}
+
+type OrderRepository interface {
+	Save(order *Order) error
+}
+
+type Order struct {
+	ID string
+}
+
+func NewOrder(id string) *Order {
+	return &Order{ID: id}
+}
+
+func (o *Order) Validate() error {
+	if o.ID == "" {
+		return errors.New("id is required")
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package tags
+
+import "time"
+
+// User は users テーブルの行と、 API で返すユーザーを表す。
+type User struct {
+	// ID はユーザーを一意に識別する。
+	ID int64 `json:"id" db:"user_id" validate:"required"`
+	// Name は表示名。
+	Name      string    `json:"name" db:"name"`
+	Email     string    `json:"email,omitempty" db:"email" validate:"email"` // Email は通知の送信先。
+	CreatedAt time.Time `db:"created_at"`
+	password  string
+	Audit
+}
+
+// Audit は更新者を記録する。
+type Audit struct {
+	UpdatedBy string `json:"updatedBy" db:"updated_by"`
+}
+
+// DisplayName は表示に使う名前を返す。
+func (u *User) DisplayName() string {
+	return u.Name
+}
+
+// UserRepository はユーザーを永続化する。
+type UserRepository interface {
+	// Find は id のユーザーを返す。
+	Find(id int64) (*User, error)
+	Save(user *User) error
+}
+
+type (
+	// Status はユーザーの状態を表す。
+	Status string
+)